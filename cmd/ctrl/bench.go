@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/LeKSuS-04/mephi-db/internal/bench"
+	"github.com/LeKSuS-04/mephi-db/internal/reports"
+)
+
+func benchCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "bench",
+		Usage: "Benchmark report queries",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "run",
+				Usage: "Runs report queries repeatedly and explains their plans",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:    "iterations",
+						Aliases: []string{"n"},
+						Usage:   "Amount of measured runs of every query",
+						Value:   10,
+					},
+					&cli.IntFlag{
+						Name:  "warmup",
+						Usage: "Amount of unmeasured runs before measuring",
+						Value: 2,
+					},
+					&cli.StringSliceFlag{
+						Name:  "only",
+						Usage: "Names of reports to benchmark, all reports are benchmarked by default",
+					},
+					rawSliceFlag("param", "Report parameter in key=value form applied to every report having it, can be repeated"),
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "File to write JSON report to",
+						Value:   "bench.json",
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					rs, err := findReports(ctx.StringSlice("only"))
+					if err != nil {
+						return err
+					}
+					params, err := reports.ParseParams(rawSlice(ctx, "param"))
+					if err != nil {
+						return err
+					}
+
+					pool, err := createPostgresConnectionPool(ctx)
+					if err != nil {
+						return fmt.Errorf("create postgres connection pool: %w", err)
+					}
+
+					run, err := bench.Benchmark(ctx.Context, pool, rs, bench.Options{
+						Iterations: ctx.Int("iterations"),
+						Warmup:     ctx.Int("warmup"),
+						Params:     params,
+					})
+					if err != nil {
+						return fmt.Errorf("run benchmark: %w", err)
+					}
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+					fmt.Fprintln(w, "REPORT\tROWS\tP50 MS\tP95 MS\tEXEC MS\tINDEXES")
+					for _, r := range run.Results {
+						fmt.Fprintf(w, "%s\t%d\t%.2f\t%.2f\t%.2f\t%s\n",
+							r.Report, r.Rows, r.Latency.P50, r.Latency.P95, r.Plan.ExecutionTime,
+							strings.Join(r.Plan.Indexes, ", "))
+					}
+					if err := w.Flush(); err != nil {
+						return err
+					}
+
+					data, err := run.Encode()
+					if err != nil {
+						return fmt.Errorf("encode benchmark: %w", err)
+					}
+					if err := os.WriteFile(ctx.String("output"), data, 0o644); err != nil {
+						return fmt.Errorf("write benchmark: %w", err)
+					}
+					log.Printf("Benchmark written to %q", ctx.String("output"))
+					return nil
+				},
+			},
+			{
+				Name:      "compare",
+				Usage:     "Compares two benchmark reports and flags regressions",
+				ArgsUsage: "<base.json> <current.json>",
+				Flags: []cli.Flag{
+					&cli.Float64Flag{
+						Name:  "threshold",
+						Usage: "Relative slowdown considered a regression",
+						Value: 0.2,
+					},
+					&cli.Float64Flag{
+						Name:  "min-delta",
+						Usage: "Absolute slowdown in milliseconds ignored as noise",
+						Value: 1,
+					},
+					&cli.StringFlag{
+						Name:  "format",
+						Usage: "Output format: table or json",
+						Value: "table",
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					if ctx.NArg() != 2 {
+						return fmt.Errorf("expected two benchmark files, got %d arguments", ctx.NArg())
+					}
+					base, err := readBenchmark(ctx.Args().Get(0))
+					if err != nil {
+						return err
+					}
+					current, err := readBenchmark(ctx.Args().Get(1))
+					if err != nil {
+						return err
+					}
+
+					diff := bench.Compare(base, current, bench.CompareOptions{
+						Threshold: ctx.Float64("threshold"),
+						MinDelta:  ctx.Float64("min-delta"),
+					})
+
+					switch ctx.String("format") {
+					case "json":
+						enc := json.NewEncoder(os.Stdout)
+						enc.SetIndent("", "  ")
+						if err := enc.Encode(diff); err != nil {
+							return err
+						}
+					case "table":
+						w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+						fmt.Fprintln(w, "REPORT\tMETRIC\tBASE MS\tCURRENT MS\tCHANGE")
+						for _, r := range diff.Regressions {
+							fmt.Fprintf(w, "%s\t%s\t%.2f\t%.2f\t%+.0f%%\n", r.Report, r.Metric, r.Base, r.Current, 100*r.Change)
+						}
+						if err := w.Flush(); err != nil {
+							return err
+						}
+						for _, c := range diff.IndexChanges {
+							fmt.Printf("%s: indexes added [%s], removed [%s]\n",
+								c.Report, strings.Join(c.Added, ", "), strings.Join(c.Removed, ", "))
+						}
+						for _, name := range diff.Missing {
+							fmt.Printf("%s: missing from current run\n", name)
+						}
+					default:
+						return fmt.Errorf("unknown format %q", ctx.String("format"))
+					}
+
+					if len(diff.Regressions) > 0 {
+						return fmt.Errorf("%d regressions found", len(diff.Regressions))
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
+
+func findReports(names []string) ([]reports.Report, error) {
+	if len(names) == 0 {
+		return reports.All(), nil
+	}
+	rs := make([]reports.Report, 0, len(names))
+	for _, name := range names {
+		r, err := reports.Find(name)
+		if err != nil {
+			return nil, err
+		}
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
+func readBenchmark(path string) (*bench.Run, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read benchmark: %w", err)
+	}
+	run, err := bench.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("decode benchmark %q: %w", path, err)
+	}
+	return run, nil
+}
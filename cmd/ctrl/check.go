@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/LeKSuS-04/mephi-db/internal/check"
+)
+
+func checkCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "check",
+		Usage: "Validates business invariants of the data",
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:  "only",
+				Usage: "Names of checks to run, all checks are run by default",
+			},
+			&cli.IntFlag{
+				Name:  "samples",
+				Usage: "Amount of offending ids to report for each check",
+				Value: 5,
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Output format: table or json",
+				Value: "table",
+			},
+			&cli.BoolFlag{
+				Name:  "list",
+				Usage: "List available checks without running them",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			checks, err := check.Find(ctx.StringSlice("only"))
+			if err != nil {
+				return err
+			}
+
+			if ctx.Bool("list") {
+				w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+				for _, c := range checks {
+					fmt.Fprintf(w, "%s\t%s\n", c.Name, c.Description)
+				}
+				return w.Flush()
+			}
+
+			pool, err := createPostgresConnectionPool(ctx)
+			if err != nil {
+				return fmt.Errorf("create postgres connection pool: %w", err)
+			}
+
+			results, err := check.Run(ctx.Context, pool, checks, ctx.Int("samples"))
+			if err != nil {
+				return fmt.Errorf("run checks: %w", err)
+			}
+
+			switch ctx.String("format") {
+			case "json":
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(results); err != nil {
+					return fmt.Errorf("encode results: %w", err)
+				}
+			case "table":
+				w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+				fmt.Fprintln(w, "CHECK\tSTATUS\tVIOLATIONS\tSAMPLE IDS")
+				for _, r := range results {
+					status := "ok"
+					if !r.Ok() {
+						status = "FAIL"
+					}
+					sample := "-"
+					if len(r.Samples) > 0 {
+						ids := make([]string, len(r.Samples))
+						for i, id := range r.Samples {
+							ids[i] = fmt.Sprint(id)
+						}
+						sample = r.Table + ": " + strings.Join(ids, ", ")
+					}
+					fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", r.Name, status, r.Violations, sample)
+				}
+				if err := w.Flush(); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("unknown format %q", ctx.String("format"))
+			}
+
+			failed := 0
+			for _, r := range results {
+				if !r.Ok() {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d checks failed", failed, len(results))
+			}
+			return nil
+		},
+	}
+}
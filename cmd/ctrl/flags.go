@@ -0,0 +1,39 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// rawValues collects every value of a repeated flag as is. Unlike values of
+// cli.StringSliceFlag they are not split on commas, which report parameters
+// and query arguments such as street addresses may contain.
+type rawValues []string
+
+func (v *rawValues) Set(value string) error {
+	*v = append(*v, value)
+	return nil
+}
+
+func (v *rawValues) String() string {
+	return strings.Join(*v, " ")
+}
+
+// rawSliceFlag returns repeatable flag keeping commas in its values, which
+// are read with rawSlice.
+func rawSliceFlag(name, usage string) cli.Flag {
+	return &cli.GenericFlag{
+		Name:  name,
+		Usage: usage,
+		Value: &rawValues{},
+	}
+}
+
+// rawSlice returns values of flag created by rawSliceFlag.
+func rawSlice(ctx *cli.Context, name string) []string {
+	if v, ok := ctx.Generic(name).(*rawValues); ok {
+		return *v
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/LeKSuS-04/mephi-db/internal/indexes"
+	"github.com/LeKSuS-04/mephi-db/internal/reports"
+)
+
+func indexImpactCommand() *cli.Command {
+	return &cli.Command{
+		Name: "index-impact",
+		Usage: "Compares report plans and timings with each secondary index present " +
+			"and hidden, listing unused and redundant indexes",
+		Description: "Every index is dropped inside a transaction which is rolled back " +
+			"afterwards. Dropping locks the table, so don't run it against busy database.",
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:  "only",
+				Usage: "Names of indexes to check, all secondary indexes are checked by default",
+			},
+			rawSliceFlag("param", "Report parameter in key=value form applied to every report having it, can be repeated"),
+			&cli.IntFlag{
+				Name:    "iterations",
+				Aliases: []string{"n"},
+				Usage:   "Amount of EXPLAIN ANALYZE runs per measurement, median is taken",
+				Value:   3,
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "Statement timeout with index hidden",
+				Value: 30 * time.Second,
+			},
+			&cli.Float64Flag{
+				Name:  "threshold",
+				Usage: "Relative slowdown considered significant",
+				Value: 0.2,
+			},
+			&cli.Float64Flag{
+				Name:  "min-delta",
+				Usage: "Absolute slowdown in milliseconds ignored as noise",
+				Value: 1,
+			},
+			&cli.BoolFlag{
+				Name:    "verbose",
+				Aliases: []string{"v"},
+				Usage:   "Print timings of every report related to index",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Output format: table or json",
+				Value: "table",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			params, err := reports.ParseParams(rawSlice(ctx, "param"))
+			if err != nil {
+				return err
+			}
+
+			pool, err := createPostgresConnectionPool(ctx)
+			if err != nil {
+				return fmt.Errorf("create postgres connection pool: %w", err)
+			}
+
+			idxs, err := indexes.List(ctx.Context, pool)
+			if err != nil {
+				return err
+			}
+			if only := ctx.StringSlice("only"); len(only) > 0 {
+				for _, name := range only {
+					if !slices.ContainsFunc(idxs, func(idx indexes.Index) bool { return idx.Name == name }) {
+						return fmt.Errorf("unknown index %q", name)
+					}
+				}
+				idxs = slices.DeleteFunc(idxs, func(idx indexes.Index) bool {
+					return !slices.Contains(only, idx.Name)
+				})
+			}
+
+			impacts, err := indexes.Measure(ctx.Context, pool, idxs, reports.All(), indexes.Options{
+				Params:     params,
+				Iterations: ctx.Int("iterations"),
+				Timeout:    ctx.Duration("timeout"),
+				Threshold:  ctx.Float64("threshold"),
+				MinDelta:   ctx.Float64("min-delta"),
+			})
+			if err != nil {
+				return fmt.Errorf("measure index impact: %w", err)
+			}
+
+			switch ctx.String("format") {
+			case "json":
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(impacts)
+			case "table":
+				return printImpacts(os.Stdout, impacts, ctx.Bool("verbose"))
+			default:
+				return fmt.Errorf("unknown format %q", ctx.String("format"))
+			}
+		},
+	}
+}
+
+func printImpacts(out io.Writer, impacts []indexes.Impact, verbose bool) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "INDEX\tTABLE\tMETHOD\tSCANS\tUSED BY\tMAX SLOWDOWN\tVERDICT\tREDUNDANT WITH")
+	for _, impact := range impacts {
+		usedBy := 0
+		timedOut := false
+		slowdown := math.Inf(-1)
+		for _, r := range impact.Reports {
+			if !r.UsesIndex {
+				continue
+			}
+			usedBy++
+			timedOut = timedOut || r.TimedOut
+			slowdown = max(slowdown, r.Slowdown())
+		}
+		maxSlowdown := ""
+		switch {
+		case timedOut:
+			maxSlowdown = "timeout"
+		case usedBy > 0:
+			maxSlowdown = fmt.Sprintf("%+.0f%%", 100*slowdown)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d/%d\t%s\t%s\t%s\n",
+			impact.Index.Name, impact.Index.Table, impact.Index.Method, impact.Index.Scans,
+			usedBy, len(impact.Reports), maxSlowdown, impact.Verdict,
+			strings.Join(impact.Index.RedundantWith, ", "))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if !verbose {
+		return nil
+	}
+
+	for _, impact := range impacts {
+		if len(impact.Reports) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "\n%s\n", impact.Index.Definition)
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "REPORT\tUSES\tWITH MS\tWITHOUT MS\tINDEXES WITHOUT")
+		for _, r := range impact.Reports {
+			without := fmt.Sprintf("%.2f", r.Without)
+			if r.TimedOut {
+				without = "timeout"
+			}
+			fmt.Fprintf(w, "%s\t%t\t%.2f\t%s\t%s\n",
+				r.Report, r.UsesIndex, r.With, without, strings.Join(r.IndexesAfter, ", "))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
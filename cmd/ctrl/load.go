@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/LeKSuS-04/mephi-db/internal/load"
+)
+
+func loadCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "load",
+		Usage: "Runs concurrent transactions against the database and reports their latency",
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:    "users",
+				Aliases: []string{"u"},
+				Usage:   "Amount of concurrent virtual users",
+				Value:   10,
+			},
+			&cli.DurationFlag{
+				Name:    "duration",
+				Aliases: []string{"d"},
+				Usage:   "How long to run transactions",
+				Value:   30 * time.Second,
+			},
+			&cli.DurationFlag{
+				Name:  "think",
+				Usage: "Pause of every virtual user between transactions",
+			},
+			&cli.StringSliceFlag{
+				Name: "mix",
+				Usage: "Transaction weight in kind=weight form, can be repeated. Kinds are " +
+					strings.Join(kindNames(), ", ") + ". Default mix is used when not set",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Output format: table or json",
+				Value: "table",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			mix := load.DefaultMix()
+			if specs := ctx.StringSlice("mix"); len(specs) > 0 {
+				var err error
+				if mix, err = load.ParseMix(specs); err != nil {
+					return err
+				}
+			}
+			format := ctx.String("format")
+			if format != "table" && format != "json" {
+				return fmt.Errorf("unknown format %q", format)
+			}
+
+			pool, err := createPostgresConnectionPool(ctx)
+			if err != nil {
+				return fmt.Errorf("create postgres connection pool: %w", err)
+			}
+			if err := requireMigrated(ctx.Context, pool); err != nil {
+				return err
+			}
+			if users := ctx.Int("users"); int(pool.Config().MaxConns) < users {
+				log.Printf("Pool has %d connections for %d virtual users, latency includes waiting for connection; "+
+					"consider raising --max-conns", pool.Config().MaxConns, users)
+			}
+
+			result, err := load.Run(ctx.Context, pool, load.Options{
+				Users:    ctx.Int("users"),
+				Duration: ctx.Duration("duration"),
+				Mix:      mix,
+				Think:    ctx.Duration("think"),
+			})
+			if err != nil {
+				return fmt.Errorf("run load: %w", err)
+			}
+
+			if format == "json" {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(result)
+			}
+			return printLoad(os.Stdout, result)
+		},
+	}
+}
+
+func kindNames() []string {
+	var names []string
+	for _, kind := range load.Kinds() {
+		names = append(names, string(kind))
+	}
+	return names
+}
+
+func printLoad(out io.Writer, r *load.Result) error {
+	fmt.Fprintf(out, "%d virtual users, %.1fs\n\n", r.Users, r.Elapsed)
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TRANSACTION\tCOUNT\tTPS\tSKIPPED\tCONFLICTS\tERRORS\tERROR RATE\tP50 MS\tP95 MS\tP99 MS\tMAX MS")
+	for _, s := range r.Stats {
+		fmt.Fprintf(w, "%s\t%d\t%.1f\t%d\t%d\t%d\t%.2f%%\t%.2f\t%.2f\t%.2f\t%.2f\n",
+			s.Kind, s.Count, s.Throughput, s.Skipped, s.Conflicts, s.Errors, 100*s.ErrorRate,
+			s.Latency.P50, s.Latency.P95, s.Latency.P99, s.Latency.Max)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(r.Stats) == 0 {
+		return nil
+	}
+	fmt.Fprintln(out)
+	w = tabwriter.NewWriter(out, 0, 4, 2, ' ', tabwriter.AlignRight)
+	header := []string{"LATENCY MS"}
+	for i, b := range r.Stats[0].Histogram {
+		if b.Le == 0 {
+			prev := r.Stats[0].Histogram[i-1].Le
+			header = append(header, ">"+strconv.FormatFloat(prev, 'f', -1, 64))
+			continue
+		}
+		header = append(header, "<="+strconv.FormatFloat(b.Le, 'f', -1, 64))
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t")+"\t")
+	for _, s := range r.Stats {
+		row := []string{string(s.Kind)}
+		for _, b := range s.Histogram {
+			row = append(row, strconv.FormatInt(b.Count, 10))
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t")+"\t")
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, s := range r.Stats {
+		if s.Error != "" {
+			fmt.Fprintf(out, "\n%s failed %d times, e.g.: %s\n", s.Kind, s.Errors, s.Error)
+		}
+	}
+	return nil
+}
@@ -1,48 +1,28 @@
 package main
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"os"
+	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/urfave/cli/v2"
+	"github.com/urfave/cli/v2/altsrc"
 
 	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
 	"github.com/LeKSuS-04/mephi-db/internal/gen"
+	"github.com/LeKSuS-04/mephi-db/internal/partitions"
 	"github.com/LeKSuS-04/mephi-db/internal/reset"
+	"github.com/LeKSuS-04/mephi-db/internal/views"
+	"github.com/LeKSuS-04/mephi-db/pkg/launcher"
 )
 
 func main() {
 	app := &cli.App{
-		Name:  "ctrl",
-		Usage: "Utility for controlling your database",
-		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    "address",
-				Aliases: []string{"a"},
-				Usage:   "Address of postgres database",
-				Value:   "localhost:5432",
-			},
-			&cli.StringFlag{
-				Name:    "user",
-				Aliases: []string{"u"},
-				Usage:   "User to connect to postgres as",
-				Value:   "postgres",
-			},
-			&cli.StringFlag{
-				Name:     "password",
-				Aliases:  []string{"p"},
-				Usage:    "Password for the postgres user",
-				Required: true,
-			},
-			&cli.StringFlag{
-				Name:  "db",
-				Usage: "Nasme of the database",
-				Value: "postgres",
-			},
-		},
+		Name:   "ctrl",
+		Usage:  "Utility for controlling your database",
+		Flags:  connectionFlags,
+		Before: altsrc.InitInputSourceWithContext(connectionFlags, configSource),
 		Commands: []*cli.Command{
 			{
 				Name:    "generate",
@@ -54,6 +34,14 @@ func main() {
 							"dummy data",
 						Value: false,
 					},
+					&cli.BoolFlag{
+						Name:  "timings",
+						Usage: "Print duration and outcome of every generation task",
+					},
+					&cli.StringFlag{
+						Name:  "trace",
+						Usage: "Path to write generation tasks to in Chrome trace event format, viewable in Perfetto",
+					},
 				},
 				Usage: "Generate dummy data",
 				Action: func(ctx *cli.Context) error {
@@ -61,6 +49,9 @@ func main() {
 					if err != nil {
 						return fmt.Errorf("create postgres connection pool: %w", err)
 					}
+					if err := requireMigrated(ctx.Context, pool); err != nil {
+						return err
+					}
 
 					if ctx.Bool("reset") {
 						if err := reset.Reset(pool); err != nil {
@@ -68,10 +59,28 @@ func main() {
 						}
 					}
 
+					partitioned, err := partitions.Enabled(ctx.Context, pool)
+					if err != nil {
+						return fmt.Errorf("check partitioning: %w", err)
+					}
+					if partitioned {
+						now := time.Now()
+						if err := partitions.EnsureRange(ctx.Context, pool, now.Add(-gen.DefaultConfig.HistoryDepth), now); err != nil {
+							return fmt.Errorf("ensure partitions: %w", err)
+						}
+					}
+
 					q := queries.New(pool)
-					if err := gen.Generate(q); err != nil {
+					spans, err := gen.Generate(q, gen.DefaultConfig)
+					if err := writeSpans(ctx, spans); err != nil {
+						return err
+					}
+					if err != nil {
 						return fmt.Errorf("generate dummy data: %w", err)
 					}
+					if err := views.Refresh(ctx.Context, pool, false); err != nil {
+						return err
+					}
 
 					return nil
 				},
@@ -88,10 +97,26 @@ func main() {
 					if err := reset.Reset(pool); err != nil {
 						return fmt.Errorf("reset db: %w", err)
 					}
+					if err := views.Refresh(ctx.Context, pool, false); err != nil {
+						return err
+					}
 
 					return nil
 				},
 			},
+			partitionsCommand(),
+			checkCommand(),
+			statsCommand(),
+			reportCommand(),
+			benchCommand(),
+			indexImpactCommand(),
+			verifyEquivalentCommand(),
+			viewsCommand(),
+			priceCommand(),
+			migrateCommand(),
+			loadCommand(),
+			replayCommand(),
+			serveCommand(),
 		},
 	}
 
@@ -100,15 +125,22 @@ func main() {
 	}
 }
 
-func createPostgresConnectionPool(ctx *cli.Context) (*pgxpool.Pool, error) {
-	user := ctx.String("user")
-	password := ctx.String("password")
-	address := ctx.String("address")
-	db := ctx.String("db")
-	connectionUri := fmt.Sprintf("postgresql://%s:%s@%s/%s", user, password, address, db)
-	pool, err := pgxpool.New(context.Background(), connectionUri)
-	if err != nil {
-		return nil, err
+func writeSpans(ctx *cli.Context, spans []launcher.Span) error {
+	if ctx.Bool("timings") {
+		if err := launcher.WriteTimings(os.Stdout, spans); err != nil {
+			return fmt.Errorf("write timings: %w", err)
+		}
+	}
+	if path := ctx.String("trace"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("create trace file: %w", err)
+		}
+		defer f.Close()
+		if err := launcher.WriteChromeTrace(f, spans); err != nil {
+			return fmt.Errorf("write trace: %w", err)
+		}
+		log.Printf("Wrote trace of %d tasks to %q", len(spans), path)
 	}
-	return pool, nil
+	return nil
 }
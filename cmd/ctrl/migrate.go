@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/urfave/cli/v2"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/migrations"
+)
+
+func migrateCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "migrate",
+		Usage: "Manage schema migrations",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "up",
+				Usage: "Applies all pending migrations",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:  "optional",
+						Usage: "Optional migration to apply after pending ones, can be repeated",
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					pool, err := createPostgresConnectionPool(ctx)
+					if err != nil {
+						return fmt.Errorf("create postgres connection pool: %w", err)
+					}
+
+					versions, err := migrations.Apply(ctx.Context, pool)
+					if err != nil {
+						return fmt.Errorf("apply migrations: %w", err)
+					}
+					optional := ctx.StringSlice("optional")
+					for _, version := range optional {
+						if err := migrations.ApplyOptional(ctx.Context, pool, version); err != nil {
+							return fmt.Errorf("apply optional migration: %w", err)
+						}
+					}
+					if len(versions) == 0 && len(optional) == 0 {
+						fmt.Println("Schema is up to date")
+					}
+
+					return nil
+				},
+			},
+			{
+				Name:  "status",
+				Usage: "Lists migrations and whether they are applied",
+				Action: func(ctx *cli.Context) error {
+					pool, err := createPostgresConnectionPool(ctx)
+					if err != nil {
+						return fmt.Errorf("create postgres connection pool: %w", err)
+					}
+
+					statuses, err := migrations.Statuses(ctx.Context, pool)
+					if err != nil {
+						return fmt.Errorf("get migration statuses: %w", err)
+					}
+					for _, s := range statuses {
+						mark := "pending"
+						if s.Optional {
+							mark = "optional"
+						}
+						if s.Applied {
+							mark = "applied"
+						}
+						fmt.Printf("%-8s %s\n", mark, s.Version)
+					}
+
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// requireMigrated fails if schema has pending migrations, so that commands
+// relying on the latest schema do not break halfway through.
+func requireMigrated(ctx context.Context, pool *pgxpool.Pool) error {
+	pending, err := migrations.Pending(ctx, pool)
+	if err != nil {
+		return fmt.Errorf("get pending migrations: %w", err)
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("schema has pending migrations %s: run ctrl migrate up", strings.Join(pending, ", "))
+	}
+	return nil
+}
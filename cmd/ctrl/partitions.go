@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/LeKSuS-04/mephi-db/internal/partitions"
+)
+
+func partitionsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "partitions",
+		Usage: "Manage monthly partitions of orders and payments",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "ensure",
+				Usage: "Creates partitions for current month and months ahead",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "ahead",
+						Usage: "Amount of future months to create partitions for",
+						Value: 3,
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					pool, err := createPostgresConnectionPool(ctx)
+					if err != nil {
+						return fmt.Errorf("create postgres connection pool: %w", err)
+					}
+
+					if err := partitions.Ensure(ctx.Context, pool, ctx.Int("ahead")); err != nil {
+						return fmt.Errorf("ensure partitions: %w", err)
+					}
+
+					return nil
+				},
+			},
+			{
+				Name:  "drop",
+				Usage: "Drops partitions older than retention period",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:     "older-than",
+						Usage:    "Retention period in months; partitions ending before it are dropped",
+						Required: true,
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					pool, err := createPostgresConnectionPool(ctx)
+					if err != nil {
+						return fmt.Errorf("create postgres connection pool: %w", err)
+					}
+
+					now := time.Now()
+					before := time.Date(now.Year(), now.Month()-time.Month(ctx.Int("older-than")), 1, 0, 0, 0, 0, time.UTC)
+					if _, err := partitions.Drop(ctx.Context, pool, before); err != nil {
+						return fmt.Errorf("drop partitions: %w", err)
+					}
+
+					return nil
+				},
+			},
+			{
+				Name:  "list",
+				Usage: "Lists existing partitions",
+				Action: func(ctx *cli.Context) error {
+					pool, err := createPostgresConnectionPool(ctx)
+					if err != nil {
+						return fmt.Errorf("create postgres connection pool: %w", err)
+					}
+
+					parts, err := partitions.List(ctx.Context, pool)
+					if err != nil {
+						return fmt.Errorf("list partitions: %w", err)
+					}
+					for _, p := range parts {
+						fmt.Printf("%-24s %-12s %s - %s\n", p.Name, p.Table, p.From.Format(time.DateOnly), p.To.Format(time.DateOnly))
+					}
+
+					return nil
+				},
+			},
+		},
+	}
+}
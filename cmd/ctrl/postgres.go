@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"net"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/urfave/cli/v2"
+	"github.com/urfave/cli/v2/altsrc"
+)
+
+const defaultConfigPath = "ctrl.yaml"
+
+// Connection settings are resolved in the following order: command line
+// flags, environment variables, config file, flag defaults. Anything left
+// unset is filled in by pgx from standard PG* environment variables and
+// ~/.pgpass.
+var connectionFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "config",
+		Usage:   "Path to yaml config file with values for global flags (default: " + defaultConfigPath + " if present)",
+		EnvVars: []string{"CTRL_CONFIG"},
+	},
+	altsrc.NewStringFlag(&cli.StringFlag{
+		Name:    "dsn",
+		Usage:   "Postgres connection string, takes precedence over address, user, password, db and sslmode",
+		EnvVars: []string{"CTRL_DSN"},
+	}),
+	altsrc.NewStringFlag(&cli.StringFlag{
+		Name:    "address",
+		Aliases: []string{"a"},
+		Usage:   "Address of postgres database in host:port form (default: $PGHOST:$PGPORT or localhost:5432)",
+	}),
+	altsrc.NewStringFlag(&cli.StringFlag{
+		Name:    "user",
+		Aliases: []string{"u"},
+		Usage:   "User to connect to postgres as",
+		Value:   "postgres",
+		EnvVars: []string{"PGUSER"},
+	}),
+	altsrc.NewStringFlag(&cli.StringFlag{
+		Name:    "password",
+		Aliases: []string{"p"},
+		Usage:   "Password for the postgres user, looked up in ~/.pgpass if not set",
+		EnvVars: []string{"PGPASSWORD"},
+	}),
+	altsrc.NewStringFlag(&cli.StringFlag{
+		Name:    "db",
+		Usage:   "Name of the database",
+		Value:   "postgres",
+		EnvVars: []string{"PGDATABASE"},
+	}),
+	altsrc.NewStringFlag(&cli.StringFlag{
+		Name:    "sslmode",
+		Usage:   "SSL mode: disable, allow, prefer, require, verify-ca or verify-full",
+		EnvVars: []string{"PGSSLMODE"},
+	}),
+	altsrc.NewStringFlag(&cli.StringFlag{
+		Name:    "application-name",
+		Usage:   "Application name reported to postgres",
+		Value:   "ctrl",
+		EnvVars: []string{"PGAPPNAME"},
+	}),
+	altsrc.NewIntFlag(&cli.IntFlag{
+		Name:  "max-conns",
+		Usage: "Maximum size of connection pool, 0 means max(4, number of CPUs)",
+	}),
+	altsrc.NewIntFlag(&cli.IntFlag{
+		Name:  "min-conns",
+		Usage: "Minimum size of connection pool",
+	}),
+	altsrc.NewDurationFlag(&cli.DurationFlag{
+		Name:  "max-conn-lifetime",
+		Usage: "Duration after which connection is closed, 0 means pgx default",
+	}),
+	altsrc.NewDurationFlag(&cli.DurationFlag{
+		Name:  "max-conn-idle-time",
+		Usage: "Duration after which idle connection is closed, 0 means pgx default",
+	}),
+}
+
+func configSource(ctx *cli.Context) (altsrc.InputSourceContext, error) {
+	path := ctx.String("config")
+	if path == "" {
+		if _, err := os.Stat(defaultConfigPath); err != nil {
+			return altsrc.NewMapInputSource("", map[any]any{}), nil
+		}
+		path = defaultConfigPath
+	}
+	return altsrc.NewYamlSourceFromFile(path)
+}
+
+func createPostgresConnectionPool(ctx *cli.Context) (*pgxpool.Pool, error) {
+	connString := ctx.String("dsn")
+	if connString == "" {
+		var err error
+		if connString, err = connectionStringFromFlags(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	config, err := pgxpool.ParseConfig(connString)
+	if err != nil {
+		return nil, fmt.Errorf("parse connection config: %w", err)
+	}
+	if ctx.IsSet("application-name") || ctx.String("dsn") == "" {
+		config.ConnConfig.RuntimeParams["application_name"] = ctx.String("application-name")
+	}
+	if n := ctx.Int("max-conns"); n > 0 {
+		config.MaxConns = int32(n)
+	}
+	if n := ctx.Int("min-conns"); n > 0 {
+		config.MinConns = int32(n)
+	}
+	if d := ctx.Duration("max-conn-lifetime"); d > 0 {
+		config.MaxConnLifetime = d
+	}
+	if d := ctx.Duration("max-conn-idle-time"); d > 0 {
+		config.MaxConnIdleTime = d
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx.Context, config)
+	if err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+// connectionStringFromFlags builds keyword/value connection string, leaving
+// out settings that are not provided so that pgx falls back to environment
+// and passfile for them.
+func connectionStringFromFlags(ctx *cli.Context) (string, error) {
+	settings := map[string]string{
+		"user":    ctx.String("user"),
+		"dbname":  ctx.String("db"),
+		"sslmode": ctx.String("sslmode"),
+	}
+	if password := ctx.String("password"); password != "" {
+		settings["password"] = password
+	}
+	address := ctx.String("address")
+	if address == "" && os.Getenv("PGHOST") == "" {
+		// pgx would otherwise prefer unix socket directory if one exists.
+		address = "localhost"
+	}
+	if address != "" {
+		host, port, err := splitAddress(address)
+		if err != nil {
+			return "", fmt.Errorf("parse address %q: %w", address, err)
+		}
+		settings["host"] = host
+		settings["port"] = port
+	}
+
+	var b strings.Builder
+	for _, key := range slices.Sorted(maps.Keys(settings)) {
+		value := settings[key]
+		if value == "" {
+			continue
+		}
+		value = strings.ReplaceAll(value, `\`, `\\`)
+		value = strings.ReplaceAll(value, `'`, `\'`)
+		fmt.Fprintf(&b, "%s='%s' ", key, value)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// splitAddress splits address into host and port, leaving port empty if
+// address has none. Bare IPv6 addresses are accepted with or without
+// brackets.
+func splitAddress(address string) (string, string, error) {
+	switch {
+	case strings.HasPrefix(address, "[") && strings.HasSuffix(address, "]"):
+		return address[1 : len(address)-1], "", nil
+	case strings.Count(address, ":") == 0:
+		return address, "", nil
+	case strings.Count(address, ":") > 1 && !strings.HasPrefix(address, "["):
+		return address, "", nil
+	}
+	return net.SplitHostPort(address)
+}
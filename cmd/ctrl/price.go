@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/urfave/cli/v2"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+	"github.com/LeKSuS-04/mephi-db/internal/pricing"
+)
+
+func priceCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "price",
+		Usage:     "Computes line prices and total of an order with discounts applied",
+		ArgsUsage: "<order id>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Output format: table or json",
+				Value: "table",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() != 1 {
+				return fmt.Errorf("expected order id, got %d arguments", ctx.NArg())
+			}
+			orderID, err := strconv.ParseInt(ctx.Args().First(), 10, 32)
+			if err != nil {
+				return fmt.Errorf("parse order id: %w", err)
+			}
+
+			pool, err := createPostgresConnectionPool(ctx)
+			if err != nil {
+				return fmt.Errorf("create postgres connection pool: %w", err)
+			}
+
+			price, err := pricing.OrderPrice(ctx.Context, queries.New(pool), int32(orderID))
+			if err != nil {
+				return fmt.Errorf("compute price: %w", err)
+			}
+
+			switch ctx.String("format") {
+			case "json":
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(price)
+			case "table":
+				return printPrice(os.Stdout, price)
+			default:
+				return fmt.Errorf("unknown format %q", ctx.String("format"))
+			}
+		},
+	}
+}
+
+func printPrice(out io.Writer, p *pricing.Price) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "POSITION\tITEM\tCOST\tQUANTITY\tREDUCTION\tPRICE\tDISCOUNT")
+	for _, l := range p.Lines {
+		item := fmt.Sprintf("commodity %d", l.Target.CommodityID.Int32)
+		if l.Target.DishID.Valid {
+			item = fmt.Sprintf("dish %d", l.Target.DishID.Int32)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%d\t%d\t%s\n",
+			l.PositionID, item, l.Cost, l.Quantity, l.Reduction, l.Price, optionalID(l.DiscountID))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(out, "\nSubtotal: %d\nDiscount: %d\nTotal:    %d\n", p.Subtotal, p.Discount, p.Total)
+	return err
+}
+
+func optionalID(id pgtype.Int4) string {
+	if !id.Valid {
+		return "-"
+	}
+	return strconv.Itoa(int(id.Int32))
+}
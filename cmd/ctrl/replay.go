@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/LeKSuS-04/mephi-db/internal/replay"
+)
+
+func replayCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "replay",
+		Usage:     "Replays captured query log and compares latency with the recorded one",
+		ArgsUsage: "<file.jsonl>",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "fast",
+				Usage: "Replay statements as fast as possible instead of keeping recorded timing",
+			},
+			&cli.IntFlag{
+				Name:  "workers",
+				Usage: "Amount of concurrent connections used with --fast",
+				Value: 8,
+			},
+			&cli.Float64Flag{
+				Name:  "scale",
+				Usage: "Speed up of recorded timing, e.g. 2 replays log twice as fast",
+				Value: 1,
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Output format: table or json",
+				Value: "table",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() != 1 {
+				return fmt.Errorf("expected log file, got %d arguments", ctx.NArg())
+			}
+			format := ctx.String("format")
+			if format != "table" && format != "json" {
+				return fmt.Errorf("unknown format %q", format)
+			}
+
+			f, err := os.Open(ctx.Args().First())
+			if err != nil {
+				return fmt.Errorf("open log: %w", err)
+			}
+			defer f.Close()
+			entries, err := replay.Read(f)
+			if err != nil {
+				return fmt.Errorf("read log %q: %w", ctx.Args().First(), err)
+			}
+
+			pool, err := createPostgresConnectionPool(ctx)
+			if err != nil {
+				return fmt.Errorf("create postgres connection pool: %w", err)
+			}
+
+			result, err := replay.Run(ctx.Context, pool, entries, replay.Options{
+				Fast:    ctx.Bool("fast"),
+				Workers: ctx.Int("workers"),
+				Scale:   ctx.Float64("scale"),
+			})
+			if err != nil {
+				return fmt.Errorf("replay log: %w", err)
+			}
+
+			if format == "json" {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(result)
+			}
+			return printReplay(os.Stdout, result)
+		},
+	}
+}
+
+func printReplay(out io.Writer, r *replay.Result) error {
+	fmt.Fprintf(out, "%d statements recorded over %.1fs replayed in %.1fs, max lag %.2f ms\n\n",
+		r.Statements, r.Recorded, r.Elapsed, r.MaxLag)
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "STATEMENT\tCOUNT\tERRORS\tRECORDED P50 MS\tREPLAYED P50 MS\tRECORDED P95 MS\tREPLAYED P95 MS\tCHANGE")
+	for _, s := range r.Results {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.2f\t%.2f\t%.2f\t%.2f\t%+.0f%%\n",
+			truncate(s.Statement, 60), s.Count, s.Errors,
+			s.Recorded.P50, s.Replayed.P50, s.Recorded.P95, s.Replayed.P95, 100*s.Change)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, s := range r.Results {
+		if s.Error != "" {
+			fmt.Fprintf(out, "\n%s failed %d times, e.g.: %s\n", truncate(s.Statement, 60), s.Errors, s.Error)
+		}
+	}
+	return nil
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-3]) + "..."
+	}
+	return s
+}
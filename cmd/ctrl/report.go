@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+	"github.com/LeKSuS-04/mephi-db/internal/reports"
+)
+
+func reportCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "report",
+		Usage: "Run analytical reports from tasks",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "Lists available reports and their parameters",
+				Action: func(ctx *cli.Context) error {
+					w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+					fmt.Fprintln(w, "TASK\tNAME\tPARAMS\tDESCRIPTION")
+					for _, r := range reports.All() {
+						params := make([]string, len(r.Params))
+						for i, p := range r.Params {
+							params[i] = fmt.Sprintf("%s:%s", p.Name, p.Kind)
+							if p.Default != "" {
+								params[i] += "=" + p.Default
+							}
+						}
+						fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Task, r.Name, strings.Join(params, " "), r.Description)
+					}
+					return w.Flush()
+				},
+			},
+			{
+				Name:        "run",
+				Usage:       "Runs report with given parameters",
+				ArgsUsage:   "<name>",
+				Subcommands: reportRunCommands(),
+			},
+		},
+	}
+}
+
+func reportRunCommands() []*cli.Command {
+	all := reports.All()
+	commands := make([]*cli.Command, 0, len(all))
+	for _, report := range all {
+		usage := report.Description
+		if report.Task != "" {
+			usage = fmt.Sprintf("Task %s: %s", report.Task, report.Description)
+		}
+		commands = append(commands, &cli.Command{
+			Name:  report.Name,
+			Usage: usage,
+			Flags: []cli.Flag{
+				rawSliceFlag("param", "Report parameter in key=value form, can be repeated"),
+				&cli.StringFlag{
+					Name:  "format",
+					Usage: "Output format: table, csv or json",
+					Value: "table",
+				},
+			},
+			Action: func(ctx *cli.Context) error {
+				params, err := reports.ParseParams(rawSlice(ctx, "param"))
+				if err != nil {
+					return err
+				}
+				args, err := report.ParseArgs(params)
+				if err != nil {
+					return err
+				}
+
+				pool, err := createPostgresConnectionPool(ctx)
+				if err != nil {
+					return fmt.Errorf("create postgres connection pool: %w", err)
+				}
+
+				result, err := report.Run(ctx.Context, queries.New(pool), args)
+				if err != nil {
+					return fmt.Errorf("run report %q: %w", report.Name, err)
+				}
+				return result.Write(os.Stdout, ctx.String("format"))
+			},
+		})
+	}
+	return commands
+}
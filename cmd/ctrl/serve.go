@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/LeKSuS-04/mephi-db/internal/api"
+)
+
+func serveCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "Serves JSON API over the database, described at /openapi.json",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "addr",
+				Usage: "Address to listen on",
+				Value: ":8080",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			pool, err := createPostgresConnectionPool(ctx)
+			if err != nil {
+				return fmt.Errorf("create postgres connection pool: %w", err)
+			}
+			defer pool.Close()
+			if err := requireMigrated(ctx.Context, pool); err != nil {
+				return err
+			}
+
+			server := &http.Server{
+				Addr:              ctx.String("addr"),
+				Handler:           api.New(pool),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+
+			sigCtx, stop := signal.NotifyContext(ctx.Context, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			go func() {
+				<-sigCtx.Done()
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				if err := server.Shutdown(shutdownCtx); err != nil {
+					log.Printf("Failed to shut down server: %v", err)
+				}
+			}()
+
+			log.Printf("Serving API on %q", server.Addr)
+			if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("serve api: %w", err)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/LeKSuS-04/mephi-db/internal/stats"
+)
+
+func statsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "stats",
+		Usage: "Prints overview of the dataset",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Output format: table or json",
+				Value: "table",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			pool, err := createPostgresConnectionPool(ctx)
+			if err != nil {
+				return fmt.Errorf("create postgres connection pool: %w", err)
+			}
+
+			s, err := stats.Collect(ctx.Context, pool)
+			if err != nil {
+				return fmt.Errorf("collect stats: %w", err)
+			}
+
+			switch ctx.String("format") {
+			case "json":
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(s)
+			case "table":
+				return printStats(os.Stdout, s)
+			default:
+				return fmt.Errorf("unknown format %q", ctx.String("format"))
+			}
+		},
+	}
+}
+
+func printStats(out io.Writer, s *stats.Stats) error {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "TABLE\tROWS\tHEAP\tINDEXES\tTOAST\tTOTAL\tPARTITIONS\t")
+	for _, t := range s.Tables {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%d\t\n",
+			t.Name, t.Rows, humanSize(t.HeapSize), humanSize(t.IndexSize),
+			humanSize(t.ToastSize), humanSize(t.TotalSize), t.Partitions)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out)
+	w = tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NULLABLE FOREIGN KEY\tREFERENCES\tNULLS\tRATIO")
+	for _, r := range s.NullableForeignKeys {
+		fmt.Fprintf(w, "%s.%s\t%s\t%d/%d\t%.2f%%\n", r.Table, r.Column, r.Target, r.Nulls, r.Rows, 100*r.Ratio)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, dist := range []struct {
+		title  string
+		shares []stats.Share
+	}{
+		{"ORDER STATUS", s.OrderStatuses},
+		{"PAYMENT METHOD", s.PaymentMethods},
+	} {
+		fmt.Fprintln(out)
+		w = tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(w, "%s\tCOUNT\tRATIO\n", dist.title)
+		for _, share := range dist.shares {
+			fmt.Fprintf(w, "%s\t%d\t%.2f%%\n", share.Value, share.Count, 100*share.Ratio)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(out)
+	if s.Orders.From == nil || s.Orders.To == nil {
+		fmt.Fprintln(out, "Orders: none")
+	} else {
+		fmt.Fprintf(out, "Orders: %s - %s\n", s.Orders.From.Format(time.DateTime), s.Orders.To.Format(time.DateTime))
+	}
+	return nil
+}
+
+func humanSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
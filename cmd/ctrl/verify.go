@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/LeKSuS-04/mephi-db/internal/equiv"
+	"github.com/LeKSuS-04/mephi-db/internal/fixtures"
+	"github.com/LeKSuS-04/mephi-db/internal/reports"
+)
+
+const reportPrefix = "report:"
+
+func verifyEquivalentCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "verify-equivalent",
+		Usage:     "Checks that two query formulations return the same rows",
+		ArgsUsage: "<a.sql | report:name> <b.sql | report:name>",
+		Description: "Both queries are run against current dataset and against edge-case " +
+			"fixtures with NULLs, duplicates and empty tables, and their results are " +
+			"compared as multisets. Fixtures are loaded in transactions which are rolled " +
+			"back, but tables are locked while comparing.",
+		Flags: []cli.Flag{
+			rawSliceFlag("arg", "Value of $1, $2, etc. placeholders in query files, in order, can be repeated"),
+			rawSliceFlag("param", "Parameter in key=value form for catalog reports, can be repeated"),
+			&cli.StringSliceFlag{
+				Name:  "fixture",
+				Usage: "Names of fixtures to compare on, all fixtures are used by default",
+			},
+			&cli.BoolFlag{
+				Name:  "skip-current",
+				Usage: "Compare on fixtures only, skipping current dataset",
+			},
+			&cli.IntFlag{
+				Name:  "samples",
+				Usage: "Amount of differing rows to print for every dataset",
+				Value: 10,
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "Output format: table or json",
+				Value: "table",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			if ctx.NArg() != 2 {
+				return fmt.Errorf("expected two queries, got %d arguments", ctx.NArg())
+			}
+			params, err := reports.ParseParams(rawSlice(ctx, "param"))
+			if err != nil {
+				return err
+			}
+			a, err := loadQuery(ctx.Args().Get(0), rawSlice(ctx, "arg"), params)
+			if err != nil {
+				return err
+			}
+			b, err := loadQuery(ctx.Args().Get(1), rawSlice(ctx, "arg"), params)
+			if err != nil {
+				return err
+			}
+
+			fs, err := fixtures.All()
+			if names := ctx.StringSlice("fixture"); len(names) > 0 {
+				fs, err = fixtures.Find(names)
+			}
+			if err != nil {
+				return err
+			}
+
+			pool, err := createPostgresConnectionPool(ctx)
+			if err != nil {
+				return fmt.Errorf("create postgres connection pool: %w", err)
+			}
+
+			diffs, err := equiv.Verify(ctx.Context, pool, a, b, fs, !ctx.Bool("skip-current"))
+			if err != nil {
+				return fmt.Errorf("verify equivalence: %w", err)
+			}
+
+			switch ctx.String("format") {
+			case "json":
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(diffs); err != nil {
+					return err
+				}
+			case "table":
+				printDiffs(os.Stdout, diffs, ctx.Int("samples"))
+			default:
+				return fmt.Errorf("unknown format %q", ctx.String("format"))
+			}
+
+			failed := 0
+			for _, diff := range diffs {
+				if !diff.Equal() {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("queries differ on %d of %d datasets", failed, len(diffs))
+			}
+			return nil
+		},
+	}
+}
+
+func loadQuery(arg string, args []string, params map[string]string) (equiv.Query, error) {
+	if name, ok := strings.CutPrefix(arg, reportPrefix); ok {
+		return equiv.FromReport(name, params)
+	}
+	return equiv.FromFile(arg, args)
+}
+
+func printDiffs(out io.Writer, diffs []equiv.Diff, samples int) {
+	for _, diff := range diffs {
+		switch {
+		case diff.Mismatch != "":
+			fmt.Fprintf(out, "%s: %s\n", diff.Dataset, diff.Mismatch)
+			continue
+		case diff.Equal():
+			fmt.Fprintf(out, "%s: equal, %d rows\n", diff.Dataset, diff.RowsA)
+			continue
+		}
+
+		fmt.Fprintf(out, "%s: differ, %d rows in a, %d rows in b\n", diff.Dataset, diff.RowsA, diff.RowsB)
+		lines := make([]string, 0, len(diff.OnlyInA)+len(diff.OnlyInB))
+		for _, row := range diff.OnlyInA {
+			lines = append(lines, fmt.Sprintf("- %v (x%d)", row.Values, row.Count))
+		}
+		for _, row := range diff.OnlyInB {
+			lines = append(lines, fmt.Sprintf("+ %v (x%d)", row.Values, row.Count))
+		}
+		for i, line := range lines {
+			if i == samples {
+				fmt.Fprintf(out, "  ...\n")
+				break
+			}
+			fmt.Fprintf(out, "  %s\n", line)
+		}
+	}
+}
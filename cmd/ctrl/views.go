@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/LeKSuS-04/mephi-db/internal/views"
+)
+
+func viewsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "views",
+		Usage: "Manage materialized reporting views",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "refresh",
+				Usage: "Recomputes materialized views",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "concurrently",
+						Usage: "Refresh without blocking reports reading views",
+					},
+					&cli.DurationFlag{
+						Name:  "watch",
+						Usage: "Keep refreshing views with given interval until interrupted",
+					},
+				},
+				Action: func(ctx *cli.Context) error {
+					pool, err := createPostgresConnectionPool(ctx)
+					if err != nil {
+						return fmt.Errorf("create postgres connection pool: %w", err)
+					}
+
+					concurrently := ctx.Bool("concurrently")
+					if err := views.Refresh(ctx.Context, pool, concurrently); err != nil {
+						return err
+					}
+
+					interval := ctx.Duration("watch")
+					if interval <= 0 {
+						return nil
+					}
+					watchCtx, stop := signal.NotifyContext(ctx.Context, os.Interrupt, syscall.SIGTERM)
+					defer stop()
+
+					ticker := time.NewTicker(interval)
+					defer ticker.Stop()
+					for {
+						select {
+						case <-watchCtx.Done():
+							return nil
+						case <-ticker.C:
+						}
+						// Failed refresh is retried on next tick rather than
+						// stopping the watch.
+						if err := views.Refresh(watchCtx, pool, concurrently); err != nil && watchCtx.Err() == nil {
+							log.Printf("Failed to refresh views: %v", err)
+						}
+					}
+				},
+			},
+		},
+	}
+}
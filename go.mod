@@ -3,16 +3,21 @@ module github.com/LeKSuS-04/mephi-db
 go 1.23.3
 
 require (
-	github.com/brianvoe/gofakeit/v7 v7.1.2 // indirect
+	github.com/brianvoe/gofakeit/v7 v7.1.2
+	github.com/jackc/pgx/v5 v5.7.1
+	github.com/urfave/cli/v2 v2.27.5
+)
+
+require (
+	github.com/BurntSushi/toml v1.4.0 // indirect
 	github.com/cpuguy83/go-md2man/v2 v2.0.5 // indirect
 	github.com/jackc/pgpassfile v1.0.0 // indirect
 	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
-	github.com/jackc/pgx/v5 v5.7.1 // indirect
 	github.com/jackc/puddle/v2 v2.2.2 // indirect
 	github.com/russross/blackfriday/v2 v2.1.0 // indirect
-	github.com/urfave/cli/v2 v2.27.5 // indirect
 	github.com/xrash/smetrics v0.0.0-20240521201337-686a1a2994c1 // indirect
 	golang.org/x/crypto v0.27.0 // indirect
 	golang.org/x/sync v0.8.0 // indirect
 	golang.org/x/text v0.18.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
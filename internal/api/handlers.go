@@ -0,0 +1,263 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+	"github.com/LeKSuS-04/mephi-db/internal/lifecycle"
+	"github.com/LeKSuS-04/mephi-db/internal/reports"
+)
+
+func (s *Server) listUsers(r *http.Request) (any, error) {
+	p := newParams(r, "surname")
+	arg := queries.ListUsersParams{Surname: p.text("surname")}
+	pg := p.page()
+	if p.err != nil {
+		return nil, p.err
+	}
+	arg.PageSize, arg.PageOffset = pg.limit, pg.offset
+
+	rows, err := s.q.ListUsers(r.Context(), arg)
+	if err != nil {
+		return nil, fmt.Errorf("list users: %w", err)
+	}
+	return newPage(pg, rows, newUser), nil
+}
+
+func (s *Server) getUser(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	u, err := s.q.SelectUser(r.Context(), id)
+	if err != nil {
+		return nil, fmt.Errorf("select user %d: %w", id, err)
+	}
+	return newUser(queries.ListUsersRow(u)), nil
+}
+
+func (s *Server) listSuppliers(r *http.Request) (any, error) {
+	p := newParams(r, "name")
+	arg := queries.ListSuppliersParams{Name: p.text("name")}
+	pg := p.page()
+	if p.err != nil {
+		return nil, p.err
+	}
+	arg.PageSize, arg.PageOffset = pg.limit, pg.offset
+
+	rows, err := s.q.ListSuppliers(r.Context(), arg)
+	if err != nil {
+		return nil, fmt.Errorf("list suppliers: %w", err)
+	}
+	return newPage(pg, rows, newSupplier), nil
+}
+
+func (s *Server) getSupplier(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	supplier, err := s.q.SelectSupplier(r.Context(), id)
+	if err != nil {
+		return nil, fmt.Errorf("select supplier %d: %w", id, err)
+	}
+	return newSupplier(supplier), nil
+}
+
+func (s *Server) getMenu(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	supplier, err := s.q.SelectSupplier(r.Context(), id)
+	if err != nil {
+		return nil, fmt.Errorf("select supplier %d: %w", id, err)
+	}
+	dishes, err := s.q.SelectSupplierDishes(r.Context(), id)
+	if err != nil {
+		return nil, fmt.Errorf("select dishes of supplier %d: %w", id, err)
+	}
+	commodities, err := s.q.SelectSupplierCommodities(r.Context(), id)
+	if err != nil {
+		return nil, fmt.Errorf("select commodities of supplier %d: %w", id, err)
+	}
+
+	menu := Menu{
+		Supplier:    newSupplier(supplier),
+		Dishes:      make([]Dish, len(dishes)),
+		Commodities: make([]Commodity, len(commodities)),
+	}
+	for i, d := range dishes {
+		menu.Dishes[i] = newDish(d)
+	}
+	for i, c := range commodities {
+		menu.Commodities[i] = newCommodity(c)
+	}
+	return menu, nil
+}
+
+func (s *Server) listDishes(r *http.Request) (any, error) {
+	p := newParams(r, "supplier_id", "name", "ingredient", "min_cost", "max_cost", "min_rating", "max_calories")
+	arg := queries.ListDishesParams{
+		SupplierID:  p.int4("supplier_id"),
+		Name:        p.text("name"),
+		Ingredient:  p.text("ingredient"),
+		MinCost:     p.int8("min_cost"),
+		MaxCost:     p.int8("max_cost"),
+		MinRating:   p.numeric("min_rating"),
+		MaxCalories: p.int4("max_calories"),
+	}
+	pg := p.page()
+	if p.err != nil {
+		return nil, p.err
+	}
+	arg.PageSize, arg.PageOffset = pg.limit, pg.offset
+
+	rows, err := s.q.ListDishes(r.Context(), arg)
+	if err != nil {
+		return nil, fmt.Errorf("list dishes: %w", err)
+	}
+	return newPage(pg, rows, newDish), nil
+}
+
+func (s *Server) getDish(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	dish, err := s.q.SelectDish(r.Context(), id)
+	if err != nil {
+		return nil, fmt.Errorf("select dish %d: %w", id, err)
+	}
+	return newDish(dish), nil
+}
+
+func (s *Server) listCommodities(r *http.Request) (any, error) {
+	p := newParams(r, "supplier_id", "name", "ingredient", "min_cost", "max_cost", "min_rating")
+	arg := queries.ListCommoditiesParams{
+		SupplierID: p.int4("supplier_id"),
+		Name:       p.text("name"),
+		Ingredient: p.text("ingredient"),
+		MinCost:    p.int8("min_cost"),
+		MaxCost:    p.int8("max_cost"),
+		MinRating:  p.numeric("min_rating"),
+	}
+	pg := p.page()
+	if p.err != nil {
+		return nil, p.err
+	}
+	arg.PageSize, arg.PageOffset = pg.limit, pg.offset
+
+	rows, err := s.q.ListCommodities(r.Context(), arg)
+	if err != nil {
+		return nil, fmt.Errorf("list commodities: %w", err)
+	}
+	return newPage(pg, rows, newCommodity), nil
+}
+
+func (s *Server) getCommodity(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	commodity, err := s.q.SelectCommodity(r.Context(), id)
+	if err != nil {
+		return nil, fmt.Errorf("select commodity %d: %w", id, err)
+	}
+	return newCommodity(commodity), nil
+}
+
+func (s *Server) listOrders(r *http.Request) (any, error) {
+	p := newParams(r, "user_id", "courier_id", "status", "since", "until")
+	arg := queries.ListOrdersParams{
+		UserID:    p.int4("user_id"),
+		CourierID: p.int4("courier_id"),
+		Status:    p.text("status"),
+		Since:     p.timestamp("since"),
+		Until:     p.timestamp("until"),
+	}
+	if arg.Status.Valid {
+		if _, err := lifecycle.Parse(arg.Status.String); err != nil {
+			p.fail("status", err.Error())
+		}
+	}
+	pg := p.page()
+	if p.err != nil {
+		return nil, p.err
+	}
+	arg.PageSize, arg.PageOffset = pg.limit, pg.offset
+
+	rows, err := s.q.ListOrders(r.Context(), arg)
+	if err != nil {
+		return nil, fmt.Errorf("list orders: %w", err)
+	}
+	return newPage(pg, rows, newOrder), nil
+}
+
+func (s *Server) getOrder(r *http.Request) (any, error) {
+	id, err := pathID(r)
+	if err != nil {
+		return nil, err
+	}
+	order, err := s.q.SelectOrder(r.Context(), id)
+	if err != nil {
+		return nil, fmt.Errorf("select order %d: %w", id, err)
+	}
+	lines, err := s.q.SelectOrderLines(r.Context(), id)
+	if err != nil {
+		return nil, fmt.Errorf("select lines of order %d: %w", id, err)
+	}
+	history, err := lifecycle.History(r.Context(), s.q, id)
+	if err != nil {
+		return nil, err
+	}
+
+	details := OrderDetails{
+		Order:   newOrder(order),
+		Lines:   make([]OrderLine, len(lines)),
+		History: history,
+	}
+	for i, l := range lines {
+		details.Lines[i] = newOrderLine(l)
+	}
+	return details, nil
+}
+
+func (s *Server) listReports(r *http.Request) (any, error) {
+	all := reports.All()
+	rs := make([]Report, len(all))
+	for i, report := range all {
+		rs[i] = newReport(report)
+	}
+	return rs, nil
+}
+
+// runReport passes every query parameter to report, so the result is not
+// paginated.
+func (s *Server) runReport(r *http.Request) (any, error) {
+	report, err := reports.Find(r.PathValue("name"))
+	if err != nil {
+		return nil, notFound("%s", err)
+	}
+	raw := make(map[string]string)
+	for name, values := range r.URL.Query() {
+		raw[name] = values[0]
+	}
+	args, err := report.ParseArgs(raw)
+	if err != nil {
+		return nil, badRequest("%s", err)
+	}
+
+	result, err := report.Run(r.Context(), s.q, args)
+	if err != nil {
+		return nil, fmt.Errorf("run report %q: %w", report.Name, err)
+	}
+	var buf bytes.Buffer
+	if err := result.WriteJSON(&buf); err != nil {
+		return nil, fmt.Errorf("encode report %q: %w", report.Name, err)
+	}
+	return json.RawMessage(buf.Bytes()), nil
+}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+)
+
+// openAPIDocument describes every route of Server. It must be updated
+// together with routes and response types.
+//
+//go:embed openapi.json
+var openAPIDocument []byte
+
+func (s *Server) openAPI(r *http.Request) (any, error) {
+	return json.RawMessage(openAPIDocument), nil
+}
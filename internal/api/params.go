@@ -0,0 +1,149 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 500
+)
+
+// params parses query parameters of request, remembering the first error,
+// so that handlers check it once after reading every filter.
+type params struct {
+	values url.Values
+	err    error
+}
+
+// newParams fails on parameters other than allowed ones and pagination, so
+// that misspelled filters are not silently ignored.
+func newParams(r *http.Request, allowed ...string) *params {
+	p := &params{values: r.URL.Query()}
+	for name := range p.values {
+		if name != "limit" && name != "offset" && !slices.Contains(allowed, name) {
+			p.fail(name, "unknown parameter")
+		}
+	}
+	return p
+}
+
+func (p *params) fail(name, reason string) {
+	if p.err == nil {
+		p.err = badRequest("parameter %q: %s", name, reason)
+	}
+}
+
+func (p *params) text(name string) pgtype.Text {
+	value := p.values.Get(name)
+	return pgtype.Text{String: value, Valid: value != ""}
+}
+
+func (p *params) int4(name string) pgtype.Int4 {
+	value := p.values.Get(name)
+	if value == "" {
+		return pgtype.Int4{}
+	}
+	n, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		p.fail(name, "expected integer")
+	}
+	return pgtype.Int4{Int32: int32(n), Valid: err == nil}
+}
+
+func (p *params) int8(name string) pgtype.Int8 {
+	value := p.values.Get(name)
+	if value == "" {
+		return pgtype.Int8{}
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		p.fail(name, "expected integer")
+	}
+	return pgtype.Int8{Int64: n, Valid: err == nil}
+}
+
+func (p *params) numeric(name string) pgtype.Numeric {
+	var n pgtype.Numeric
+	value := p.values.Get(name)
+	if value == "" {
+		return n
+	}
+	if _, err := strconv.ParseFloat(value, 64); err != nil {
+		p.fail(name, "expected number")
+		return n
+	}
+	if err := n.Scan(value); err != nil {
+		p.fail(name, "expected number")
+	}
+	return n
+}
+
+// timestamp accepts either date or RFC 3339 timestamp. Timestamps with
+// offset are converted to UTC, since timestamps in database have no zone
+// and are compared by their wall clock.
+func (p *params) timestamp(name string) pgtype.Timestamp {
+	value := p.values.Get(name)
+	if value == "" {
+		return pgtype.Timestamp{}
+	}
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return pgtype.Timestamp{Time: t.UTC(), Valid: true}
+		}
+	}
+	p.fail(name, "expected date or RFC 3339 timestamp")
+	return pgtype.Timestamp{}
+}
+
+type page struct {
+	limit  int32
+	offset int32
+}
+
+func (p *params) page() page {
+	pg := page{limit: defaultLimit}
+	if limit := p.int4("limit"); limit.Valid {
+		if limit.Int32 < 1 || limit.Int32 > maxLimit {
+			p.fail("limit", fmt.Sprintf("must be between 1 and %d", maxLimit))
+		}
+		pg.limit = limit.Int32
+	}
+	if offset := p.int4("offset"); offset.Valid {
+		if offset.Int32 < 0 {
+			p.fail("offset", "must not be negative")
+		}
+		pg.offset = offset.Int32
+	}
+	return pg
+}
+
+// Page is a single page of list response.
+type Page[T any] struct {
+	Items  []T   `json:"items"`
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+func newPage[R, T any](pg page, rows []R, convert func(R) T) Page[T] {
+	items := make([]T, len(rows))
+	for i, row := range rows {
+		items[i] = convert(row)
+	}
+	return Page[T]{Items: items, Limit: pg.limit, Offset: pg.offset}
+}
+
+func pathID(r *http.Request) (int32, error) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 32)
+	if err != nil {
+		return 0, badRequest("id %q is not an integer", r.PathValue("id"))
+	}
+	return int32(id), nil
+}
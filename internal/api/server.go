@@ -0,0 +1,110 @@
+// Package api serves read-only JSON API over the delivery database.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+)
+
+type Server struct {
+	q   *queries.Queries
+	mux *http.ServeMux
+	// patterns are registered routes, which openapi.json must describe.
+	patterns []string
+}
+
+func New(pg *pgxpool.Pool) *Server {
+	s := &Server{
+		q:   queries.New(pg),
+		mux: http.NewServeMux(),
+	}
+
+	s.route("GET /openapi.json", s.openAPI)
+	s.route("GET /users", s.listUsers)
+	s.route("GET /users/{id}", s.getUser)
+	s.route("GET /suppliers", s.listSuppliers)
+	s.route("GET /suppliers/{id}", s.getSupplier)
+	s.route("GET /suppliers/{id}/menu", s.getMenu)
+	s.route("GET /dishes", s.listDishes)
+	s.route("GET /dishes/{id}", s.getDish)
+	s.route("GET /commodities", s.listCommodities)
+	s.route("GET /commodities/{id}", s.getCommodity)
+	s.route("GET /orders", s.listOrders)
+	s.route("GET /orders/{id}", s.getOrder)
+	s.route("GET /reports", s.listReports)
+	s.route("GET /reports/{name}", s.runReport)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handler returns value to be encoded as JSON response body.
+type handler func(r *http.Request) (any, error)
+
+func (s *Server) route(pattern string, h handler) {
+	s.patterns = append(s.patterns, pattern)
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		status := http.StatusOK
+		body, err := h(r)
+		if err != nil {
+			status, body = errorResponse(r, err)
+		}
+		writeJSON(w, status, body)
+		log.Printf("%s %s %d %s", r.Method, r.URL.RequestURI(), status, time.Since(start).Round(time.Microsecond))
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(body); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
+
+type httpError struct {
+	status  int
+	message string
+}
+
+func (e *httpError) Error() string {
+	return e.message
+}
+
+func badRequest(format string, args ...any) error {
+	return &httpError{status: http.StatusBadRequest, message: fmt.Sprintf(format, args...)}
+}
+
+func notFound(format string, args ...any) error {
+	return &httpError{status: http.StatusNotFound, message: fmt.Sprintf(format, args...)}
+}
+
+type errorBody struct {
+	Error string `json:"error"`
+}
+
+func errorResponse(r *http.Request, err error) (int, errorBody) {
+	var herr *httpError
+	if errors.As(err, &herr) {
+		return herr.status, errorBody{herr.message}
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return http.StatusNotFound, errorBody{"not found"}
+	}
+	log.Printf("%s %s failed: %v", r.Method, r.URL.RequestURI(), err)
+	return http.StatusInternalServerError, errorBody{"internal error"}
+}
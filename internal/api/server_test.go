@@ -0,0 +1,183 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/LeKSuS-04/mephi-db/internal/fixtures"
+	"github.com/LeKSuS-04/mephi-db/internal/tempdb/tempdbtest"
+)
+
+// get serves request to path and decodes JSON response into body unless it
+// is nil.
+func get(t *testing.T, s *Server, path string, body any) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	if body != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), body); err != nil {
+			t.Fatalf("GET %s: decode %q: %v", path, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+// TestBadRequests checks requests rejected before querying database, so
+// server runs without one.
+func TestBadRequests(t *testing.T) {
+	s := New(nil)
+	tests := []struct {
+		path   string
+		status int
+		error  string
+	}{
+		{"/users?limit=0", http.StatusBadRequest, `parameter "limit": must be between 1 and 500`},
+		{"/users?limit=501", http.StatusBadRequest, `parameter "limit": must be between 1 and 500`},
+		{"/users?limit=ten", http.StatusBadRequest, `parameter "limit": expected integer`},
+		{"/users?offset=-1", http.StatusBadRequest, `parameter "offset": must not be negative`},
+		{"/users?surnmae=Ivanov", http.StatusBadRequest, `parameter "surnmae": unknown parameter`},
+		{"/dishes?supplier_id=1&color=red", http.StatusBadRequest, `parameter "color": unknown parameter`},
+		{"/users/abc", http.StatusBadRequest, `id "abc" is not an integer`},
+		{"/orders/9999999999", http.StatusBadRequest, `id "9999999999" is not an integer`},
+		{"/orders?since=yesterday", http.StatusBadRequest, `parameter "since": expected date or RFC 3339 timestamp`},
+		{"/orders?status=flying", http.StatusBadRequest, `parameter "status": `},
+		{"/reports/no_such_report", http.StatusNotFound, ``},
+		{"/reports/dishes_ordered_between?color=red", http.StatusBadRequest, `report "dishes_ordered_between" has no parameter "color"`},
+		{"/reports/dishes_ordered_between?since=March", http.StatusBadRequest, `parse parameter "since": `},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var body errorBody
+			if status := get(t, s, tt.path, &body); status != tt.status {
+				t.Errorf("got status %d, want %d", status, tt.status)
+			}
+			if !strings.HasPrefix(body.Error, tt.error) || body.Error == "" {
+				t.Errorf("got error %q, want it to start with %q", body.Error, tt.error)
+			}
+		})
+	}
+}
+
+func TestTimestampOffset(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"2024-03-01", "2024-03-01 00:00:00"},
+		{"2024-03-01T12:30:00Z", "2024-03-01 12:30:00"},
+		{"2024-03-01T12:30:00+03:00", "2024-03-01 09:30:00"},
+		{"2024-03-01T01:00:00-02:00", "2024-03-01 03:00:00"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/orders?since="+strings.ReplaceAll(tt.value, "+", "%2B"), nil)
+		p := newParams(r, "since")
+		ts := p.timestamp("since")
+		if p.err != nil {
+			t.Errorf("%s: %v", tt.value, p.err)
+			continue
+		}
+		if got := ts.Time.Format("2006-01-02 15:04:05"); got != tt.want || ts.Time.Location() != time.UTC {
+			t.Errorf("%s: got %s in %s, want %s UTC", tt.value, got, ts.Time.Location(), tt.want)
+		}
+	}
+}
+
+// TestOpenAPIPaths checks that openapi.json describes exactly the routes
+// server has.
+func TestOpenAPIPaths(t *testing.T) {
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(openAPIDocument, &doc); err != nil {
+		t.Fatalf("decode openapi.json: %v", err)
+	}
+	var documented []string
+	for path, operations := range doc.Paths {
+		for method := range operations {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+	slices.Sort(documented)
+
+	routes := slices.Sorted(slices.Values(New(nil).patterns))
+	for _, route := range routes {
+		if !slices.Contains(documented, route) {
+			t.Errorf("route %q is not documented in openapi.json", route)
+		}
+	}
+	for _, path := range documented {
+		if !slices.Contains(routes, path) {
+			t.Errorf("openapi.json documents %q which is not routed", path)
+		}
+	}
+}
+
+func TestWithDatabase(t *testing.T) {
+	server := tempdbtest.Start(t, "../../sql/schema.sql")
+	ctx := context.Background()
+
+	seed, err := fixtures.Find([]string{"seed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = pgx.BeginFunc(ctx, server.Pool, func(tx pgx.Tx) error {
+		return fixtures.Load(ctx, tx, seed[0])
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(server.Pool)
+
+	t.Run("pagination", func(t *testing.T) {
+		var all, page Page[json.RawMessage]
+		if status := get(t, s, "/users", &all); status != http.StatusOK {
+			t.Fatalf("got status %d", status)
+		}
+		if all.Limit != defaultLimit || all.Offset != 0 || len(all.Items) < 3 {
+			t.Fatalf("got limit %d, offset %d and %d users", all.Limit, all.Offset, len(all.Items))
+		}
+		if status := get(t, s, "/users?limit=2&offset=1", &page); status != http.StatusOK {
+			t.Fatalf("got status %d", status)
+		}
+		if page.Limit != 2 || page.Offset != 1 || !slices.EqualFunc(page.Items, all.Items[1:3], jsonEqual) {
+			t.Errorf("got page %+v, want users 2 and 3 of %+v", page, all)
+		}
+		if status := get(t, s, "/users?offset=1000", &page); status != http.StatusOK || len(page.Items) != 0 {
+			t.Errorf("got status %d and %d users past the end, want empty page", status, len(page.Items))
+		}
+	})
+
+	t.Run("missing ids", func(t *testing.T) {
+		for _, path := range []string{"/users/100000", "/suppliers/100000", "/suppliers/100000/menu", "/dishes/100000", "/commodities/100000", "/orders/100000"} {
+			var body errorBody
+			if status := get(t, s, path, &body); status != http.StatusNotFound {
+				t.Errorf("GET %s: got status %d with error %q, want 404", path, status, body.Error)
+			}
+		}
+		if status := get(t, s, "/users/1", nil); status != http.StatusOK {
+			t.Errorf("GET /users/1: got status %d", status)
+		}
+	})
+
+	t.Run("report args", func(t *testing.T) {
+		var rows []json.RawMessage
+		if status := get(t, s, "/reports/dishes_ordered_between", &rows); status != http.StatusOK || len(rows) == 0 {
+			t.Errorf("with defaults: got status %d and %d rows", status, len(rows))
+		}
+		if status := get(t, s, "/reports/dishes_ordered_between?since=2030-01-01&until=2030-01-31", &rows); status != http.StatusOK || len(rows) != 0 {
+			t.Errorf("with args: got status %d and %d rows, want none", status, len(rows))
+		}
+	})
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	return string(a) == string(b)
+}
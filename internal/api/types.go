@@ -0,0 +1,190 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+	"github.com/LeKSuS-04/mephi-db/internal/lifecycle"
+	"github.com/LeKSuS-04/mephi-db/internal/reports"
+)
+
+// Response types mirror models of queries package. Nullable columns are
+// encoded as null, images and password hashes are never exposed.
+
+type User struct {
+	ID      int32       `json:"id"`
+	Name    pgtype.Text `json:"name"`
+	Surname pgtype.Text `json:"surname"`
+	Email   string      `json:"email"`
+	Phone   pgtype.Text `json:"phone"`
+}
+
+type Supplier struct {
+	ID            int32          `json:"id"`
+	Name          string         `json:"name"`
+	WorkTimeStart string         `json:"work_time_start"`
+	WorkTimeEnd   string         `json:"work_time_end"`
+	Rating        pgtype.Numeric `json:"rating"`
+	Address       string         `json:"address"`
+}
+
+type Dish struct {
+	ID          int32          `json:"id"`
+	SupplierID  int32          `json:"supplier_id"`
+	Name        string         `json:"name"`
+	Cost        int64          `json:"cost"`
+	Ingredients pgtype.Text    `json:"ingredients"`
+	Weight      int32          `json:"weight"`
+	Calories    int32          `json:"calories"`
+	Allergens   string         `json:"allergens"`
+	Rating      pgtype.Numeric `json:"rating"`
+}
+
+type Commodity struct {
+	ID          int32          `json:"id"`
+	SupplierID  int32          `json:"supplier_id"`
+	Name        string         `json:"name"`
+	Cost        int64          `json:"cost"`
+	Ingredients string         `json:"ingredients"`
+	Weight      int32          `json:"weight"`
+	Rating      pgtype.Numeric `json:"rating"`
+}
+
+type Menu struct {
+	Supplier    Supplier    `json:"supplier"`
+	Dishes      []Dish      `json:"dishes"`
+	Commodities []Commodity `json:"commodities"`
+}
+
+type Order struct {
+	ID             int32            `json:"id"`
+	UserID         int32            `json:"user_id"`
+	Timestamp      pgtype.Timestamp `json:"timestamp"`
+	SourceAddress  pgtype.Text      `json:"source_address"`
+	TargetAddress  pgtype.Text      `json:"target_address"`
+	CourierID      pgtype.Int4      `json:"courier_id"`
+	Status         string           `json:"status"`
+	PaymentID      pgtype.Int4      `json:"payment_id"`
+	DiscountID     pgtype.Int4      `json:"discount_id"`
+	TotalAmount    pgtype.Int8      `json:"total_amount"`
+	DiscountAmount pgtype.Int8      `json:"discount_amount"`
+}
+
+// OrderDetails is order with its composition and status history.
+type OrderDetails struct {
+	Order
+	Lines   []OrderLine       `json:"lines"`
+	History []lifecycle.Event `json:"history"`
+}
+
+type OrderLine struct {
+	ID          int32       `json:"id"`
+	DishID      pgtype.Int4 `json:"dish_id"`
+	CommodityID pgtype.Int4 `json:"commodity_id"`
+	UnitPrice   int64       `json:"unit_price"`
+	Quantity    int32       `json:"quantity"`
+}
+
+type Report struct {
+	Name        string        `json:"name"`
+	Task        string        `json:"task"`
+	Description string        `json:"description"`
+	Params      []ReportParam `json:"params"`
+}
+
+type ReportParam struct {
+	Name        string       `json:"name"`
+	Kind        reports.Kind `json:"kind"`
+	Default     string       `json:"default,omitempty"`
+	Required    bool         `json:"required"`
+	Description string       `json:"description"`
+}
+
+func newUser(u queries.ListUsersRow) User {
+	return User{ID: u.ID, Name: u.Name, Surname: u.Surname, Email: u.Email, Phone: u.Phone}
+}
+
+func newSupplier(s queries.Supplier) Supplier {
+	return Supplier{
+		ID:            s.ID,
+		Name:          s.Name,
+		WorkTimeStart: formatTime(s.WorkTimeStart),
+		WorkTimeEnd:   formatTime(s.WorkTimeEnd),
+		Rating:        s.Rating,
+		Address:       s.Address,
+	}
+}
+
+func formatTime(t pgtype.Time) string {
+	d := time.Duration(t.Microseconds) * time.Microsecond
+	return fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
+
+func newDish(d queries.Dish) Dish {
+	return Dish{
+		ID:          d.ID,
+		SupplierID:  d.SupplierID,
+		Name:        d.Name,
+		Cost:        d.Cost,
+		Ingredients: d.Ingredients,
+		Weight:      d.Weight,
+		Calories:    d.Calories,
+		Allergens:   d.Allergens,
+		Rating:      d.Rating,
+	}
+}
+
+func newCommodity(c queries.Commodity) Commodity {
+	return Commodity{
+		ID:          c.ID,
+		SupplierID:  c.SupplierID,
+		Name:        c.Name,
+		Cost:        c.Cost,
+		Ingredients: c.Ingredients,
+		Weight:      c.Weight,
+		Rating:      c.Rating,
+	}
+}
+
+func newOrder(o queries.Order) Order {
+	return Order{
+		ID:             o.ID,
+		UserID:         o.UserID,
+		Timestamp:      o.Timestamp,
+		SourceAddress:  o.SourceAddress,
+		TargetAddress:  o.TargetAddress,
+		CourierID:      o.CourierID,
+		Status:         o.Status,
+		PaymentID:      o.PaymentID,
+		DiscountID:     o.DiscountID,
+		TotalAmount:    o.TotalAmount,
+		DiscountAmount: o.DiscountAmount,
+	}
+}
+
+func newOrderLine(l queries.SelectOrderLinesRow) OrderLine {
+	return OrderLine{
+		ID:          l.ID,
+		DishID:      l.DishID,
+		CommodityID: l.CommodityID,
+		UnitPrice:   l.UnitPrice,
+		Quantity:    l.Quantity,
+	}
+}
+
+func newReport(r reports.Report) Report {
+	params := make([]ReportParam, len(r.Params))
+	for i, p := range r.Params {
+		params[i] = ReportParam{
+			Name:        p.Name,
+			Kind:        p.Kind,
+			Default:     p.Default,
+			Required:    p.Default == "",
+			Description: p.Description,
+		}
+	}
+	return Report{Name: r.Name, Task: r.Task, Description: r.Description, Params: params}
+}
@@ -0,0 +1,168 @@
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/LeKSuS-04/mephi-db/internal/reports"
+)
+
+type Options struct {
+	Iterations int
+	Warmup     int
+	// Params are used for every report having parameter with such name.
+	// Reports with required parameters missing from here are skipped.
+	Params map[string]string
+}
+
+type Run struct {
+	StartedAt  time.Time `json:"started_at"`
+	Iterations int       `json:"iterations"`
+	Warmup     int       `json:"warmup"`
+	Results    []Result  `json:"results"`
+}
+
+type Result struct {
+	Report  string            `json:"report"`
+	Task    string            `json:"task"`
+	Params  map[string]string `json:"params,omitempty"`
+	Rows    int64             `json:"rows"`
+	Latency Latency           `json:"latency"`
+	Plan    Plan              `json:"plan"`
+}
+
+// Latency values are in milliseconds and measure full round trip including
+// fetching all rows.
+type Latency struct {
+	Min  float64 `json:"min"`
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P95  float64 `json:"p95"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+// Benchmark runs given reports one after another and explains each of them.
+func Benchmark(ctx context.Context, pg *pgxpool.Pool, rs []reports.Report, opts Options) (*Run, error) {
+	run := &Run{
+		StartedAt:  time.Now(),
+		Iterations: opts.Iterations,
+		Warmup:     opts.Warmup,
+	}
+
+	for _, r := range rs {
+		params := r.SelectParams(opts.Params)
+		args, err := r.ParseArgs(params)
+		if err != nil {
+			log.Printf("Skipping report %q: %v", r.Name, err)
+			continue
+		}
+		stmt, err := r.Statement(args)
+		if err != nil {
+			return nil, fmt.Errorf("build statement of %q: %w", r.Name, err)
+		}
+
+		log.Printf("Benchmarking report %q", r.Name)
+		result, err := benchmarkStatement(ctx, pg, stmt, opts)
+		if err != nil {
+			return nil, fmt.Errorf("benchmark %q: %w", r.Name, err)
+		}
+		result.Report = r.Name
+		result.Task = r.Task
+		if len(params) > 0 {
+			result.Params = params
+		}
+		run.Results = append(run.Results, *result)
+	}
+	return run, nil
+}
+
+func benchmarkStatement(ctx context.Context, pg *pgxpool.Pool, stmt reports.Statement, opts Options) (*Result, error) {
+	var result Result
+	for range opts.Warmup {
+		if _, err := execute(ctx, pg, stmt); err != nil {
+			return nil, err
+		}
+	}
+
+	durations := make([]time.Duration, 0, opts.Iterations)
+	for range opts.Iterations {
+		start := time.Now()
+		rows, err := execute(ctx, pg, stmt)
+		if err != nil {
+			return nil, err
+		}
+		durations = append(durations, time.Since(start))
+		result.Rows = rows
+	}
+	result.Latency = Summarize(durations)
+
+	plan, err := Explain(ctx, pg, stmt)
+	if err != nil {
+		return nil, fmt.Errorf("explain: %w", err)
+	}
+	result.Plan = *plan
+	return &result, nil
+}
+
+func execute(ctx context.Context, pg *pgxpool.Pool, stmt reports.Statement) (int64, error) {
+	rows, err := pg.Query(ctx, stmt.SQL, stmt.Args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	var n int64
+	for rows.Next() {
+		n++
+	}
+	return n, rows.Err()
+}
+
+// Summarize computes latency percentiles of durations.
+func Summarize(durations []time.Duration) Latency {
+	if len(durations) == 0 {
+		return Latency{}
+	}
+	ms := make([]float64, len(durations))
+	var sum float64
+	for i, d := range durations {
+		ms[i] = float64(d) / float64(time.Millisecond)
+		sum += ms[i]
+	}
+	slices.Sort(ms)
+	return Latency{
+		Min:  ms[0],
+		Mean: sum / float64(len(ms)),
+		P50:  percentile(ms, 0.50),
+		P90:  percentile(ms, 0.90),
+		P95:  percentile(ms, 0.95),
+		P99:  percentile(ms, 0.99),
+		Max:  ms[len(ms)-1],
+	}
+}
+
+// percentile uses nearest-rank method on sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(0, min(rank, len(sorted)-1))]
+}
+
+func (r *Run) Encode() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+func Decode(data []byte) (*Run, error) {
+	var r Run
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
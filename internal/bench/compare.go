@@ -0,0 +1,90 @@
+package bench
+
+import (
+	"slices"
+)
+
+type CompareOptions struct {
+	// Threshold is relative slowdown considered a regression, e.g. 0.2 for
+	// 20%.
+	Threshold float64
+	// MinDelta is absolute slowdown in milliseconds below which differences
+	// are treated as noise.
+	MinDelta float64
+}
+
+type Regression struct {
+	Report  string  `json:"report"`
+	Metric  string  `json:"metric"`
+	Base    float64 `json:"base"`
+	Current float64 `json:"current"`
+	Change  float64 `json:"change"`
+}
+
+type IndexChange struct {
+	Report  string   `json:"report"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+type Diff struct {
+	Regressions  []Regression  `json:"regressions"`
+	IndexChanges []IndexChange `json:"index_changes"`
+	// Missing lists reports present in base run but absent in current one.
+	Missing []string `json:"missing"`
+}
+
+// Compare matches results of two runs by report name and flags metrics that
+// got slower by more than allowed.
+func Compare(base, current *Run, opts CompareOptions) Diff {
+	var diff Diff
+	for _, b := range base.Results {
+		idx := slices.IndexFunc(current.Results, func(r Result) bool { return r.Report == b.Report })
+		if idx == -1 {
+			diff.Missing = append(diff.Missing, b.Report)
+			continue
+		}
+		c := current.Results[idx]
+
+		for _, m := range []struct {
+			name          string
+			base, current float64
+		}{
+			{"p50", b.Latency.P50, c.Latency.P50},
+			{"p95", b.Latency.P95, c.Latency.P95},
+			{"execution_time", b.Plan.ExecutionTime, c.Plan.ExecutionTime},
+		} {
+			delta := m.current - m.base
+			if delta <= opts.MinDelta || m.base <= 0 || delta/m.base <= opts.Threshold {
+				continue
+			}
+			diff.Regressions = append(diff.Regressions, Regression{
+				Report:  b.Report,
+				Metric:  m.name,
+				Base:    m.base,
+				Current: m.current,
+				Change:  delta / m.base,
+			})
+		}
+
+		change := IndexChange{
+			Report:  b.Report,
+			Added:   difference(c.Plan.Indexes, b.Plan.Indexes),
+			Removed: difference(b.Plan.Indexes, c.Plan.Indexes),
+		}
+		if len(change.Added) > 0 || len(change.Removed) > 0 {
+			diff.IndexChanges = append(diff.IndexChanges, change)
+		}
+	}
+	return diff
+}
+
+func difference(a, b []string) []string {
+	var result []string
+	for _, v := range a {
+		if !slices.Contains(b, v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
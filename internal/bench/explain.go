@@ -0,0 +1,80 @@
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+	"github.com/LeKSuS-04/mephi-db/internal/reports"
+)
+
+// Plan holds EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) output along with values
+// extracted from it. Times are in milliseconds.
+type Plan struct {
+	PlanningTime     float64         `json:"planning_time"`
+	ExecutionTime    float64         `json:"execution_time"`
+	SharedHitBlocks  int64           `json:"shared_hit_blocks"`
+	SharedReadBlocks int64           `json:"shared_read_blocks"`
+	Indexes          []string        `json:"indexes"`
+	Raw              json.RawMessage `json:"raw"`
+}
+
+// Explain executes statement under EXPLAIN ANALYZE and collects names of
+// indexes used anywhere in the plan. db may be a pool as well as a transaction.
+func Explain(ctx context.Context, db queries.DBTX, stmt reports.Statement) (*Plan, error) {
+	var raw []byte
+	query := "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) " + stmt.SQL
+	if err := db.QueryRow(ctx, query, stmt.Args...).Scan(&raw); err != nil {
+		return nil, err
+	}
+
+	var explained []struct {
+		Plan struct {
+			SharedHitBlocks  int64 `json:"Shared Hit Blocks"`
+			SharedReadBlocks int64 `json:"Shared Read Blocks"`
+		} `json:"Plan"`
+		PlanningTime  float64 `json:"Planning Time"`
+		ExecutionTime float64 `json:"Execution Time"`
+	}
+	if err := json.Unmarshal(raw, &explained); err != nil {
+		return nil, fmt.Errorf("decode plan: %w", err)
+	}
+	if len(explained) != 1 {
+		return nil, fmt.Errorf("expected single plan, got %d", len(explained))
+	}
+
+	var tree any
+	if err := json.Unmarshal(raw, &tree); err != nil {
+		return nil, fmt.Errorf("decode plan: %w", err)
+	}
+	indexes := make(map[string]struct{})
+	collectIndexes(tree, indexes)
+
+	return &Plan{
+		PlanningTime:     explained[0].PlanningTime,
+		ExecutionTime:    explained[0].ExecutionTime,
+		SharedHitBlocks:  explained[0].Plan.SharedHitBlocks,
+		SharedReadBlocks: explained[0].Plan.SharedReadBlocks,
+		Indexes:          slices.Sorted(maps.Keys(indexes)),
+		Raw:              raw,
+	}, nil
+}
+
+func collectIndexes(node any, indexes map[string]struct{}) {
+	switch n := node.(type) {
+	case map[string]any:
+		if name, ok := n["Index Name"].(string); ok {
+			indexes[name] = struct{}{}
+		}
+		for _, child := range n {
+			collectIndexes(child, indexes)
+		}
+	case []any:
+		for _, child := range n {
+			collectIndexes(child, indexes)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Check is a named business invariant which can not be expressed by the
+// schema itself.
+type Check struct {
+	Name        string
+	Description string
+	Table       string
+	Query       string
+}
+
+type Result struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Table       string  `json:"table"`
+	Violations  int64   `json:"violations"`
+	Samples     []int32 `json:"samples"`
+}
+
+func (r Result) Ok() bool {
+	return r.Violations == 0
+}
+
+// All returns all registered checks.
+func All() []Check {
+	return slices.Clone(checks)
+}
+
+// Find returns registered checks with given names, or all of them if no
+// names are given.
+func Find(names []string) ([]Check, error) {
+	if len(names) == 0 {
+		return All(), nil
+	}
+
+	found := make([]Check, 0, len(names))
+	for _, name := range names {
+		idx := slices.IndexFunc(checks, func(c Check) bool { return c.Name == name })
+		if idx == -1 {
+			return nil, fmt.Errorf("unknown check %q", name)
+		}
+		found = append(found, checks[idx])
+	}
+	return found, nil
+}
+
+// Run executes checks one by one, collecting up to sampleSize offending ids
+// for each of them.
+func Run(ctx context.Context, pg *pgxpool.Pool, checks []Check, sampleSize int) ([]Result, error) {
+	results := make([]Result, 0, len(checks))
+	for _, c := range checks {
+		result := Result{
+			Name:        c.Name,
+			Description: c.Description,
+			Table:       c.Table,
+		}
+		query := "SELECT count(*), coalesce((array_agg(id ORDER BY id))[1:$1], '{}') FROM (" + c.Query + ") violations"
+		if err := pg.QueryRow(ctx, query, sampleSize).Scan(&result.Violations, &result.Samples); err != nil {
+			return nil, fmt.Errorf("run check %q: %w", c.Name, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,84 @@
+package check
+
+// Every query selects ids of rows from Table violating the invariant.
+var checks = []Check{
+	{
+		Name:        "orders_composition_single_target",
+		Description: "Order composition references exactly one of dish and commodity",
+		Table:       "orders_composition",
+		Query:       `SELECT id FROM orders_composition WHERE (dish_id IS NULL) = (commodity_id IS NULL)`,
+	},
+	{
+		Name:        "discount_to_targets_single_target",
+		Description: "Discount target references exactly one of dish and commodity",
+		Table:       "discount_to_targets",
+		Query:       `SELECT id FROM discount_to_targets WHERE (dish_id IS NULL) = (commodity_id IS NULL)`,
+	},
+	{
+		Name:        "categories_to_targets_single_target",
+		Description: "Category target references exactly one of dish and commodity",
+		Table:       "categories_to_targets",
+		Query:       `SELECT id FROM categories_to_targets WHERE (dish_id IS NULL) = (commodity_id IS NULL)`,
+	},
+	{
+		Name:        "online_payment_has_card",
+		Description: "Payments with method online reference a card",
+		Table:       "payments",
+		Query:       `SELECT id FROM payments WHERE method = 'online' AND card_id IS NULL`,
+	},
+	{
+		Name:        "payment_card_belongs_to_user",
+		Description: "Card used to pay for an order belongs to the user who made it",
+		Table:       "payments",
+		Query: `
+			SELECT p.id
+			FROM payments p
+			JOIN orders o ON o.payment_id = p.id
+			JOIN user_cards uc ON uc.id = p.card_id
+			WHERE uc.user_id IS DISTINCT FROM o.user_id`,
+	},
+	{
+		Name:        "order_status_matches_history",
+		Description: "Order status is the latest status from its history",
+		Table:       "orders",
+		Query: `
+			SELECT o.id
+			FROM orders o
+			JOIN (
+				SELECT DISTINCT ON (order_id) order_id, status
+				FROM order_status_history
+				ORDER BY order_id, timestamp DESC, id DESC
+			) h ON h.order_id = o.id
+			WHERE h.status <> o.status`,
+	},
+	{
+		Name:        "supplier_work_time",
+		Description: "Supplier work time starts before it ends",
+		Table:       "suppliers",
+		Query:       `SELECT id FROM suppliers WHERE work_time_start >= work_time_end`,
+	},
+	{
+		Name:        "courier_rating_range",
+		Description: "Courier rating is in [1, 5]",
+		Table:       "couriers",
+		Query:       `SELECT id FROM couriers WHERE rating NOT BETWEEN 1 AND 5`,
+	},
+	{
+		Name:        "supplier_rating_range",
+		Description: "Supplier rating is in [1, 5]",
+		Table:       "suppliers",
+		Query:       `SELECT id FROM suppliers WHERE rating NOT BETWEEN 1 AND 5`,
+	},
+	{
+		Name:        "dish_rating_range",
+		Description: "Dish rating is in [1, 5]",
+		Table:       "dishes",
+		Query:       `SELECT id FROM dishes WHERE rating NOT BETWEEN 1 AND 5`,
+	},
+	{
+		Name:        "commodity_rating_range",
+		Description: "Commodity rating is in [1, 5]",
+		Table:       "commodities",
+		Query:       `SELECT id FROM commodities WHERE rating NOT BETWEEN 1 AND 5`,
+	},
+}
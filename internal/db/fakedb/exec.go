@@ -0,0 +1,9 @@
+package fakedb
+
+// execs lists update statements used by generator. Fake database doesn't
+// emulate them, since their SQL is covered by tests against postgres, and
+// answers as if no rows were affected.
+var execs = []string{
+	"FillOrderAmounts",
+	"SyncOrderStatuses",
+}
@@ -0,0 +1,183 @@
+// Package fakedb provides in-memory queries.DBTX which is enough to run
+// generator without postgres, so that its invariants can be checked
+// quickly.
+package fakedb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var ErrUnsupported = errors.New("statement is not supported by fake database")
+
+// Row is a single row inserted with CopyFrom. Values are kept as passed by
+// generated queries, e.g. nullable foreign keys are pgtype.Int4.
+type Row struct {
+	ID     int32
+	Values map[string]any
+}
+
+type table struct {
+	rows   []Row
+	nextID int32
+}
+
+// DB keeps rows inserted with CopyFrom per table and assigns them serial
+// ids starting from 1. It answers plain selects of columns of a whole
+// table, such as Select*IDs queries, and accepts the few update statements
+// used by generator without applying them. Anything else fails with
+// ErrUnsupported.
+type DB struct {
+	mu     sync.Mutex
+	tables map[string]*table
+}
+
+func New() *DB {
+	return &DB{tables: make(map[string]*table)}
+}
+
+func (db *DB) table(name string) *table {
+	t, ok := db.tables[name]
+	if !ok {
+		t = &table{nextID: 1}
+		db.tables[name] = t
+	}
+	return t
+}
+
+// Tables returns names of tables having any rows, sorted.
+func (db *DB) Tables() []string {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	names := make([]string, 0, len(db.tables))
+	for name, t := range db.tables {
+		if len(t.rows) > 0 {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Rows returns copy of rows of table in insertion order.
+func (db *DB) Rows(table string) []Row {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return slices.Clone(db.table(table).rows)
+}
+
+func (db *DB) Count(table string) int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return len(db.table(table).rows)
+}
+
+func (db *DB) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	if len(tableName) != 1 {
+		return 0, fmt.Errorf("%w: copy to %v", ErrUnsupported, tableName)
+	}
+
+	var rows []Row
+	for rowSrc.Next() {
+		values, err := rowSrc.Values()
+		if err != nil {
+			return 0, err
+		}
+		if len(values) != len(columnNames) {
+			return 0, fmt.Errorf("copy to %q: got %d values for %d columns", tableName[0], len(values), len(columnNames))
+		}
+		row := Row{Values: make(map[string]any, len(columnNames))}
+		for i, column := range columnNames {
+			row.Values[column] = values[i]
+		}
+		rows = append(rows, row)
+	}
+	if err := rowSrc.Err(); err != nil {
+		return 0, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	t := db.table(tableName[0])
+	for i := range rows {
+		rows[i].ID = t.nextID
+		rows[i].Values["id"] = t.nextID
+		t.nextID++
+	}
+	t.rows = append(t.rows, rows...)
+	return int64(len(rows)), nil
+}
+
+var selectAll = regexp.MustCompile(`(?is)^SELECT\s+([\w\s,]+?)\s+FROM\s+(\w+)\s*;?\s*$`)
+
+func (db *DB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	r, err := db.query(sql, args)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (db *DB) query(sql string, args []any) (*rows, error) {
+	name, body := parse(sql)
+	m := selectAll.FindStringSubmatch(body)
+	if m == nil || len(args) > 0 {
+		return nil, fmt.Errorf("%w: query %s", ErrUnsupported, name)
+	}
+
+	columns := strings.Split(m[1], ",")
+	for i := range columns {
+		columns[i] = strings.TrimSpace(columns[i])
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	t := db.table(m[2])
+	values := make([][]any, len(t.rows))
+	for i, row := range t.rows {
+		values[i] = make([]any, len(columns))
+		for j, column := range columns {
+			value, ok := row.Values[column]
+			if !ok {
+				return nil, fmt.Errorf("query %s: table %q has no column %q", name, m[2], column)
+			}
+			values[i][j] = value
+		}
+	}
+	return &rows{columns: columns, values: values, idx: -1}, nil
+}
+
+func (db *DB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	r, err := db.query(sql, args)
+	if err != nil {
+		return errRow{err}
+	}
+	return &singleRow{r}
+}
+
+func (db *DB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	name, _ := parse(sql)
+	if !slices.Contains(execs, name) || len(args) > 0 {
+		return pgconn.CommandTag{}, fmt.Errorf("%w: exec %s", ErrUnsupported, name)
+	}
+	return pgconn.NewCommandTag("UPDATE 0"), nil
+}
+
+var queryName = regexp.MustCompile(`^-- name: (\w+) :\w+\n`)
+
+// parse splits statement generated by sqlc into query name and SQL.
+func parse(sql string) (string, string) {
+	m := queryName.FindStringSubmatch(sql)
+	if m == nil {
+		return "unnamed statement", strings.TrimSpace(sql)
+	}
+	return m[1], strings.TrimSpace(sql[len(m[0]):])
+}
@@ -0,0 +1,134 @@
+package fakedb
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// rows is pgx.Rows over values selected from fake tables.
+type rows struct {
+	columns []string
+	values  [][]any
+	idx     int
+	err     error
+}
+
+func (r *rows) Close() {}
+
+func (r *rows) Err() error {
+	return r.err
+}
+
+func (r *rows) CommandTag() pgconn.CommandTag {
+	return pgconn.NewCommandTag(fmt.Sprintf("SELECT %d", len(r.values)))
+}
+
+func (r *rows) FieldDescriptions() []pgconn.FieldDescription {
+	fields := make([]pgconn.FieldDescription, len(r.columns))
+	for i, column := range r.columns {
+		fields[i] = pgconn.FieldDescription{Name: column}
+	}
+	return fields
+}
+
+func (r *rows) Next() bool {
+	if r.err != nil || r.idx+1 >= len(r.values) {
+		return false
+	}
+	r.idx++
+	return true
+}
+
+func (r *rows) Scan(dest ...any) error {
+	if len(dest) != len(r.columns) {
+		r.err = fmt.Errorf("scan %d columns into %d values", len(r.columns), len(dest))
+		return r.err
+	}
+	for i, d := range dest {
+		if err := assign(d, r.values[r.idx][i]); err != nil {
+			r.err = fmt.Errorf("scan column %q: %w", r.columns[i], err)
+			return r.err
+		}
+	}
+	return nil
+}
+
+func (r *rows) Values() ([]any, error) {
+	return r.values[r.idx], nil
+}
+
+func (r *rows) RawValues() [][]byte {
+	return nil
+}
+
+func (r *rows) Conn() *pgx.Conn {
+	return nil
+}
+
+// assign stores value into dest pointer. Values of different integer types
+// are converted, pgtype values are passed through their driver values.
+func assign(dest, value any) error {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return fmt.Errorf("destination %T is not a pointer", dest)
+	}
+	target := dv.Elem()
+	if value == nil {
+		target.SetZero()
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch {
+	case v.Type().AssignableTo(target.Type()):
+		target.Set(v)
+		return nil
+	case v.CanInt() && target.CanInt():
+		target.SetInt(v.Int())
+		return nil
+	}
+
+	if scanner, ok := dest.(sql.Scanner); ok {
+		src := value
+		if valuer, ok := value.(driver.Valuer); ok {
+			var err error
+			if src, err = valuer.Value(); err != nil {
+				return err
+			}
+		}
+		return scanner.Scan(src)
+	}
+	if valuer, ok := value.(driver.Valuer); ok {
+		src, err := valuer.Value()
+		if err != nil {
+			return err
+		}
+		return assign(dest, src)
+	}
+	return fmt.Errorf("cannot assign %T to %T", value, dest)
+}
+
+// singleRow is pgx.Row returning the first of rows.
+type singleRow struct {
+	rows *rows
+}
+
+func (r *singleRow) Scan(dest ...any) error {
+	if !r.rows.Next() {
+		return pgx.ErrNoRows
+	}
+	return r.rows.Scan(dest...)
+}
+
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(dest ...any) error {
+	return r.err
+}
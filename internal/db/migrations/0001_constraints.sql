@@ -0,0 +1,23 @@
+-- Constraints for invariants that were previously only upheld by generator.
+
+ALTER TABLE users ALTER COLUMN email SET NOT NULL;
+
+ALTER TABLE orders ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE orders ALTER COLUMN status SET NOT NULL;
+ALTER TABLE orders ADD CONSTRAINT orders_status_valid
+    CHECK (status IN ('in_progress', 'delivered', 'canceled'));
+
+ALTER TABLE payments ADD CONSTRAINT payments_method_valid
+    CHECK (method IN ('cash', 'card', 'online'));
+ALTER TABLE payments ADD CONSTRAINT payments_status_valid
+    CHECK (status IN ('successful', 'failed'));
+
+ALTER TABLE discounts ADD CONSTRAINT discounts_type_valid
+    CHECK (type IN ('percentage', 'const', 'extra_for_free'));
+
+ALTER TABLE orders_composition ADD CONSTRAINT orders_composition_single_target
+    CHECK ((dish_id IS NULL) <> (commodity_id IS NULL));
+ALTER TABLE discount_to_targets ADD CONSTRAINT discount_to_targets_single_target
+    CHECK ((dish_id IS NULL) <> (commodity_id IS NULL));
+ALTER TABLE categories_to_targets ADD CONSTRAINT categories_to_targets_single_target
+    CHECK ((dish_id IS NULL) <> (commodity_id IS NULL));
@@ -0,0 +1,43 @@
+-- Materialized views with aggregates that reports used to recompute from
+-- orders_composition on every run. They are refreshed by `ctrl views
+-- refresh` and after generating data; unique indexes allow refreshing them
+-- concurrently.
+
+CREATE MATERIALIZED VIEW order_totals AS
+SELECT o.id AS order_id,
+       o.user_id,
+       o.timestamp,
+       COUNT(oc.id) AS positions,
+       COALESCE(SUM(COALESCE(d.cost, 0) + COALESCE(c.cost, 0)), 0)::BIGINT AS total
+FROM orders o
+LEFT JOIN orders_composition oc ON o.id = oc.order_id
+LEFT JOIN dishes d ON oc.dish_id = d.id
+LEFT JOIN commodities c ON oc.commodity_id = c.id
+GROUP BY o.id, o.user_id, o.timestamp;
+
+CREATE UNIQUE INDEX order_totals_order_id ON order_totals (order_id);
+CREATE INDEX order_totals_user_id ON order_totals (user_id);
+
+CREATE MATERIALIZED VIEW user_monthly_orders AS
+SELECT user_id,
+       DATE_TRUNC('month', timestamp) AS month,
+       COUNT(*) AS orders_count
+FROM orders
+GROUP BY user_id, DATE_TRUNC('month', timestamp);
+
+CREATE UNIQUE INDEX user_monthly_orders_user_month ON user_monthly_orders (user_id, month);
+
+CREATE MATERIALIZED VIEW supplier_monthly_sales AS
+SELECT COALESCE(d.supplier_id, c.supplier_id) AS supplier_id,
+       DATE_TRUNC('month', o.timestamp) AS month,
+       COUNT(d.id) AS dishes_ordered,
+       COUNT(c.id) AS commodities_ordered,
+       COUNT(DISTINCT o.id) AS orders_count,
+       SUM(COALESCE(d.cost, 0) + COALESCE(c.cost, 0))::BIGINT AS revenue
+FROM orders_composition oc
+JOIN orders o ON oc.order_id = o.id
+LEFT JOIN dishes d ON oc.dish_id = d.id
+LEFT JOIN commodities c ON oc.commodity_id = c.id
+GROUP BY COALESCE(d.supplier_id, c.supplier_id), DATE_TRUNC('month', o.timestamp);
+
+CREATE UNIQUE INDEX supplier_monthly_sales_supplier_month ON supplier_monthly_sales (supplier_id, month);
@@ -0,0 +1,68 @@
+-- Discount amount of an order, following the same rules as Go pricing
+-- package: active discounts apply to their targets, discount attached to the
+-- order applies to every position if it has no targets, and units of the
+-- same item get the single discount reducing their sum the most.
+
+-- Items are all units of the same target bought at the same price, they
+-- are what discounts are applied to.
+CREATE FUNCTION order_items(p_order_id INT)
+RETURNS TABLE (dish_id INT, commodity_id INT, cost BIGINT, units BIGINT) AS $$
+    SELECT oc.dish_id,
+           oc.commodity_id,
+           COALESCE(d.cost, c.cost),
+           COUNT(*)
+    FROM orders_composition oc
+    LEFT JOIN dishes d ON oc.dish_id = d.id
+    LEFT JOIN commodities c ON oc.commodity_id = c.id
+    WHERE oc.order_id = p_order_id
+    GROUP BY oc.dish_id, oc.commodity_id, COALESCE(d.cost, c.cost);
+$$ LANGUAGE sql STABLE;
+
+CREATE FUNCTION order_discount_amount(p_order_id INT) RETURNS BIGINT AS $$
+    WITH candidates AS (
+        SELECT ds.type, ds.terms, dt.dish_id, dt.commodity_id, dt.id IS NULL AS whole_order
+        FROM discounts ds
+        LEFT JOIN discount_to_targets dt ON ds.id = dt.discount_id
+        WHERE ds.active
+        AND (dt.id IS NOT NULL OR ds.id = (SELECT o.discount_id FROM orders o WHERE o.id = p_order_id))
+    ),
+    reductions AS (
+        SELECT MAX(LEAST(i.units * i.cost, CASE cd.type
+            WHEN 'percentage' THEN
+                i.units * (i.cost * (cd.terms->>'percentage')::BIGINT / 100)
+            WHEN 'const' THEN
+                i.units * LEAST((cd.terms->>'value')::BIGINT, i.cost)
+            WHEN 'extra_for_free' THEN
+                i.cost * (
+                    i.units / ((cd.terms->>'must_by')::BIGINT + (cd.terms->>'get_for_free')::BIGINT)
+                        * (cd.terms->>'get_for_free')::BIGINT
+                    + GREATEST(0, i.units % ((cd.terms->>'must_by')::BIGINT + (cd.terms->>'get_for_free')::BIGINT)
+                        - (cd.terms->>'must_by')::BIGINT)
+                )
+            ELSE 0
+        END)) AS reduction
+        FROM order_items(p_order_id) i
+        JOIN candidates cd ON cd.whole_order OR cd.dish_id = i.dish_id OR cd.commodity_id = i.commodity_id
+        GROUP BY i.dish_id, i.commodity_id, i.cost
+    )
+    SELECT COALESCE(SUM(reduction), 0)::BIGINT FROM reductions;
+$$ LANGUAGE sql STABLE;
+
+DROP MATERIALIZED VIEW order_totals;
+
+CREATE MATERIALIZED VIEW order_totals AS
+SELECT o.id AS order_id,
+       o.user_id,
+       o.timestamp,
+       COUNT(oc.id) AS positions,
+       COALESCE(SUM(COALESCE(d.cost, 0) + COALESCE(c.cost, 0)), 0)::BIGINT AS total,
+       order_discount_amount(o.id) AS discount,
+       (COALESCE(SUM(COALESCE(d.cost, 0) + COALESCE(c.cost, 0)), 0) - order_discount_amount(o.id))::BIGINT AS discounted_total
+FROM orders o
+LEFT JOIN orders_composition oc ON o.id = oc.order_id
+LEFT JOIN dishes d ON oc.dish_id = d.id
+LEFT JOIN commodities c ON oc.commodity_id = c.id
+GROUP BY o.id, o.user_id, o.timestamp;
+
+CREATE UNIQUE INDEX order_totals_order_id ON order_totals (order_id);
+CREATE INDEX order_totals_user_id ON order_totals (user_id);
@@ -0,0 +1,81 @@
+-- Prices customers actually paid. Line prices and order amounts are stored
+-- when an order is created, so later changes of dishes and commodities
+-- costs do not rewrite history. Existing rows are backfilled from current
+-- costs, which is the best guess available for them.
+
+ALTER TABLE orders_composition
+    ADD COLUMN unit_price BIGINT,
+    ADD COLUMN quantity INTEGER NOT NULL DEFAULT 1;
+
+UPDATE orders_composition oc
+SET unit_price = COALESCE(
+    (SELECT d.cost FROM dishes d WHERE d.id = oc.dish_id),
+    (SELECT c.cost FROM commodities c WHERE c.id = oc.commodity_id)
+);
+
+ALTER TABLE orders_composition
+    ALTER COLUMN unit_price SET NOT NULL,
+    ADD CONSTRAINT orders_composition_unit_price_non_negative CHECK (unit_price >= 0),
+    ADD CONSTRAINT orders_composition_quantity_positive CHECK (quantity > 0);
+
+ALTER TABLE orders
+    ADD COLUMN total_amount BIGINT,
+    ADD COLUMN discount_amount BIGINT,
+    ADD CONSTRAINT orders_amounts_valid CHECK (discount_amount BETWEEN 0 AND total_amount);
+
+-- Discounts apply to recorded prices and quantities of positions.
+CREATE OR REPLACE FUNCTION order_items(p_order_id INT)
+RETURNS TABLE (dish_id INT, commodity_id INT, cost BIGINT, units BIGINT) AS $$
+    SELECT oc.dish_id,
+           oc.commodity_id,
+           oc.unit_price,
+           SUM(oc.quantity)::BIGINT
+    FROM orders_composition oc
+    WHERE oc.order_id = p_order_id
+    GROUP BY oc.dish_id, oc.commodity_id, oc.unit_price;
+$$ LANGUAGE sql STABLE;
+
+UPDATE orders o
+SET total_amount = COALESCE((
+        SELECT SUM(oc.unit_price * oc.quantity)
+        FROM orders_composition oc
+        WHERE oc.order_id = o.id
+    ), 0),
+    discount_amount = order_discount_amount(o.id);
+
+-- Views fall back to computing amounts for orders created without them.
+
+DROP MATERIALIZED VIEW order_totals;
+
+CREATE MATERIALIZED VIEW order_totals AS
+SELECT o.id AS order_id,
+       o.user_id,
+       o.timestamp,
+       COUNT(oc.id) AS positions,
+       COALESCE(o.total_amount, SUM(oc.unit_price * oc.quantity), 0)::BIGINT AS total,
+       COALESCE(o.discount_amount, order_discount_amount(o.id)) AS discount,
+       (COALESCE(o.total_amount, SUM(oc.unit_price * oc.quantity), 0)
+           - COALESCE(o.discount_amount, order_discount_amount(o.id)))::BIGINT AS discounted_total
+FROM orders o
+LEFT JOIN orders_composition oc ON o.id = oc.order_id
+GROUP BY o.id, o.user_id, o.timestamp, o.total_amount, o.discount_amount;
+
+CREATE UNIQUE INDEX order_totals_order_id ON order_totals (order_id);
+CREATE INDEX order_totals_user_id ON order_totals (user_id);
+
+DROP MATERIALIZED VIEW supplier_monthly_sales;
+
+CREATE MATERIALIZED VIEW supplier_monthly_sales AS
+SELECT COALESCE(d.supplier_id, c.supplier_id) AS supplier_id,
+       DATE_TRUNC('month', o.timestamp) AS month,
+       COUNT(d.id) AS dishes_ordered,
+       COUNT(c.id) AS commodities_ordered,
+       COUNT(DISTINCT o.id) AS orders_count,
+       SUM(oc.unit_price * oc.quantity)::BIGINT AS revenue
+FROM orders_composition oc
+JOIN orders o ON oc.order_id = o.id
+LEFT JOIN dishes d ON oc.dish_id = d.id
+LEFT JOIN commodities c ON oc.commodity_id = c.id
+GROUP BY COALESCE(d.supplier_id, c.supplier_id), DATE_TRUNC('month', o.timestamp);
+
+CREATE UNIQUE INDEX supplier_monthly_sales_supplier_month ON supplier_monthly_sales (supplier_id, month);
@@ -0,0 +1,61 @@
+-- Several units of the same item are a single position with quantity
+-- instead of identical rows. Existing duplicates are collapsed into the
+-- earliest of them.
+
+WITH duplicates AS (
+    SELECT MIN(id) AS keep_id, SUM(quantity) AS quantity, ARRAY_AGG(id) AS ids
+    FROM orders_composition
+    GROUP BY order_id, dish_id, commodity_id, unit_price
+    HAVING COUNT(*) > 1
+),
+collapsed AS (
+    UPDATE orders_composition oc
+    SET quantity = dp.quantity
+    FROM duplicates dp
+    WHERE oc.id = dp.keep_id
+)
+DELETE FROM orders_composition oc
+USING duplicates dp
+WHERE oc.id = ANY(dp.ids) AND oc.id <> dp.keep_id;
+
+ALTER TABLE orders_composition
+    ADD CONSTRAINT orders_composition_position_unique
+    UNIQUE NULLS NOT DISTINCT (order_id, dish_id, commodity_id, unit_price);
+
+-- Positions are distinct items of the order, items are all units of them.
+
+DROP MATERIALIZED VIEW order_totals;
+
+CREATE MATERIALIZED VIEW order_totals AS
+SELECT o.id AS order_id,
+       o.user_id,
+       o.timestamp,
+       COUNT(oc.id) AS positions,
+       COALESCE(SUM(oc.quantity), 0)::BIGINT AS items,
+       COALESCE(o.total_amount, SUM(oc.unit_price * oc.quantity), 0)::BIGINT AS total,
+       COALESCE(o.discount_amount, order_discount_amount(o.id)) AS discount,
+       (COALESCE(o.total_amount, SUM(oc.unit_price * oc.quantity), 0)
+           - COALESCE(o.discount_amount, order_discount_amount(o.id)))::BIGINT AS discounted_total
+FROM orders o
+LEFT JOIN orders_composition oc ON o.id = oc.order_id
+GROUP BY o.id, o.user_id, o.timestamp, o.total_amount, o.discount_amount;
+
+CREATE UNIQUE INDEX order_totals_order_id ON order_totals (order_id);
+CREATE INDEX order_totals_user_id ON order_totals (user_id);
+
+DROP MATERIALIZED VIEW supplier_monthly_sales;
+
+CREATE MATERIALIZED VIEW supplier_monthly_sales AS
+SELECT COALESCE(d.supplier_id, c.supplier_id) AS supplier_id,
+       DATE_TRUNC('month', o.timestamp) AS month,
+       COALESCE(SUM(oc.quantity) FILTER (WHERE d.id IS NOT NULL), 0)::BIGINT AS dishes_ordered,
+       COALESCE(SUM(oc.quantity) FILTER (WHERE c.id IS NOT NULL), 0)::BIGINT AS commodities_ordered,
+       COUNT(DISTINCT o.id) AS orders_count,
+       SUM(oc.unit_price * oc.quantity)::BIGINT AS revenue
+FROM orders_composition oc
+JOIN orders o ON oc.order_id = o.id
+LEFT JOIN dishes d ON oc.dish_id = d.id
+LEFT JOIN commodities c ON oc.commodity_id = c.id
+GROUP BY COALESCE(d.supplier_id, c.supplier_id), DATE_TRUNC('month', o.timestamp);
+
+CREATE UNIQUE INDEX supplier_monthly_sales_supplier_month ON supplier_monthly_sales (supplier_id, month);
@@ -0,0 +1,43 @@
+-- Orders go through created -> paid -> accepted -> picked_up -> delivered.
+-- Unpaid orders may be canceled, paid ones are refunded instead. Allowed
+-- transitions are enforced by lifecycle package, every transition is
+-- recorded in order_status_history and orders.status keeps the latest one.
+
+ALTER TABLE orders DROP CONSTRAINT orders_status_valid;
+
+UPDATE orders SET status = 'accepted' WHERE status = 'in_progress';
+
+ALTER TABLE orders
+    ALTER COLUMN status SET DEFAULT 'created',
+    ADD CONSTRAINT orders_status_valid
+        CHECK (status IN ('created', 'paid', 'accepted', 'picked_up', 'delivered', 'canceled', 'refunded'));
+
+CREATE TABLE order_status_history (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    status TEXT NOT NULL,
+    timestamp TIMESTAMP NOT NULL,
+    CONSTRAINT order_status_history_status_valid
+        CHECK (status IN ('created', 'paid', 'accepted', 'picked_up', 'delivered', 'canceled', 'refunded'))
+);
+
+CREATE INDEX order_status_history_order_id ON order_status_history USING BTREE (order_id, timestamp);
+
+-- Earlier transitions of existing orders are unknown, so their history is
+-- the shortest path from created to the current status. Steps are a second
+-- apart starting at the time order was made, so that they are ordered by
+-- timestamp alone. Orders without time are taken as made right now.
+INSERT INTO order_status_history (order_id, status, timestamp)
+SELECT o.id, s.status, COALESCE(o.timestamp, LOCALTIMESTAMP) + (s.step - 1) * INTERVAL '1 second'
+FROM orders o
+JOIN (VALUES
+    ('created', ARRAY['created']),
+    ('paid', ARRAY['created', 'paid']),
+    ('accepted', ARRAY['created', 'paid', 'accepted']),
+    ('picked_up', ARRAY['created', 'paid', 'accepted', 'picked_up']),
+    ('delivered', ARRAY['created', 'paid', 'accepted', 'picked_up', 'delivered']),
+    ('canceled', ARRAY['created', 'canceled']),
+    ('refunded', ARRAY['created', 'paid', 'refunded'])
+) AS p(status, path) ON p.status = o.status
+CROSS JOIN LATERAL unnest(p.path) WITH ORDINALITY AS s(status, step)
+ORDER BY o.id, s.step;
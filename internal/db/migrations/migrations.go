@@ -0,0 +1,208 @@
+package migrations
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Migrations in optional directory change schema in ways not every
+// deployment wants, e.g. partitioning. They are never applied by Apply and
+// are not pending, but have to be requested with ApplyOptional.
+//
+//go:embed *.sql optional/*.sql
+var files embed.FS
+
+var ErrUnknown = errors.New("unknown migration")
+
+type Migration struct {
+	Version string
+	SQL     string
+}
+
+type Status struct {
+	Version  string
+	Applied  bool
+	Optional bool
+}
+
+// List returns all embedded regular migrations ordered by version.
+func List() ([]Migration, error) {
+	return list("*.sql")
+}
+
+// ListOptional returns all embedded optional migrations ordered by version.
+func ListOptional() ([]Migration, error) {
+	return list("optional/*.sql")
+}
+
+func list(pattern string) ([]Migration, error) {
+	names, err := fs.Glob(files, pattern)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+
+	migrations := make([]Migration, 0, len(names))
+	for _, name := range names {
+		sql, err := files.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("read migration %q: %w", name, err)
+		}
+		migrations = append(migrations, Migration{
+			Version: strings.TrimSuffix(path.Base(name), ".sql"),
+			SQL:     string(sql),
+		})
+	}
+	return migrations, nil
+}
+
+// Statuses reports for every known migration whether it has been applied.
+// Optional migrations follow regular ones.
+func Statuses(ctx context.Context, pg *pgxpool.Pool) ([]Status, error) {
+	migrations, err := List()
+	if err != nil {
+		return nil, err
+	}
+	optional, err := ListOptional()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(ctx, pg)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(migrations)+len(optional))
+	for _, m := range migrations {
+		_, ok := applied[m.Version]
+		statuses = append(statuses, Status{Version: m.Version, Applied: ok})
+	}
+	for _, m := range optional {
+		_, ok := applied[m.Version]
+		statuses = append(statuses, Status{Version: m.Version, Applied: ok, Optional: true})
+	}
+	return statuses, nil
+}
+
+// Pending returns versions of regular migrations that are not applied yet.
+func Pending(ctx context.Context, pg *pgxpool.Pool) ([]string, error) {
+	statuses, err := Statuses(ctx, pg)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []string
+	for _, s := range statuses {
+		if !s.Applied && !s.Optional {
+			pending = append(pending, s.Version)
+		}
+	}
+	return pending, nil
+}
+
+// Apply runs all pending migrations in order, each in its own transaction,
+// and returns versions of applied ones.
+func Apply(ctx context.Context, pg *pgxpool.Pool) ([]string, error) {
+	migrations, err := List()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(ctx, pg)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		if err := apply(ctx, pg, m); err != nil {
+			return versions, err
+		}
+		versions = append(versions, m.Version)
+	}
+	return versions, nil
+}
+
+// ApplyOptional runs optional migration with given version unless it is
+// already applied. Regular migrations must be applied before it, since
+// optional ones may make them fail afterwards.
+func ApplyOptional(ctx context.Context, pg *pgxpool.Pool, version string) error {
+	optional, err := ListOptional()
+	if err != nil {
+		return err
+	}
+	idx := slices.IndexFunc(optional, func(m Migration) bool { return m.Version == version })
+	if idx < 0 {
+		return fmt.Errorf("%w %q", ErrUnknown, version)
+	}
+
+	pending, err := Pending(ctx, pg)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("apply optional migration %q: migrations %s are pending", version, strings.Join(pending, ", "))
+	}
+	applied, err := appliedVersions(ctx, pg)
+	if err != nil {
+		return err
+	}
+	if _, ok := applied[version]; ok {
+		return nil
+	}
+	return apply(ctx, pg, optional[idx])
+}
+
+func apply(ctx context.Context, pg *pgxpool.Pool, m Migration) error {
+	log.Printf("Applying migration %q", m.Version)
+	err := pgx.BeginFunc(ctx, pg, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, m.SQL); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", m.Version)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("apply migration %q: %w", m.Version, err)
+	}
+	return nil
+}
+
+func appliedVersions(ctx context.Context, pg *pgxpool.Pool) (map[string]struct{}, error) {
+	_, err := pg.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version TEXT PRIMARY KEY,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`)
+	if err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	rows, err := pg.Query(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("select applied migrations: %w", err)
+	}
+	versions, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("select applied migrations: %w", err)
+	}
+
+	applied := make(map[string]struct{}, len(versions))
+	for _, v := range versions {
+		applied[v] = struct{}{}
+	}
+	return applied, nil
+}
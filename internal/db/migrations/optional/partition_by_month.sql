@@ -0,0 +1,129 @@
+-- Optional migration converting orders and payments into tables
+-- range-partitioned by month. Apply it only after all regular migrations:
+-- primary keys of partitioned tables become (id, timestamp), so later
+-- migrations adding foreign keys to orders or payments would fail.
+--
+-- Foreign keys referencing orders and payments can not be recreated for the
+-- same reason and are dropped, so applying this migration gives up database
+-- level integrity of orders_composition.order_id, orders.payment_id and
+-- order_status_history.order_id.
+
+-- Creates a single month partition of tbl covering the month that contains
+-- the given date. Partitions are named <tbl>_pYYYYMM so that tooling can find
+-- their bounds without parsing pg_class.relpartbound.
+CREATE OR REPLACE FUNCTION create_month_partition(tbl TEXT, month DATE) RETURNS TEXT AS $$
+DECLARE
+    first DATE := date_trunc('month', month)::DATE;
+    part TEXT := format('%s_p%s', tbl, to_char(month, 'YYYYMM'));
+BEGIN
+    EXECUTE format(
+        'CREATE TABLE IF NOT EXISTS %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)',
+        part, tbl, first, (first + INTERVAL '1 month')::DATE
+    );
+    RETURN part;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Converts a plain table into a table range-partitioned by month on col.
+--
+-- Primary key of the partitioned table becomes (id, col), because postgres
+-- requires partition key to be a part of every unique constraint. Foreign
+-- keys referencing tbl(id) are dropped. Views and materialized views
+-- selecting from tbl are recreated along with their indexes.
+CREATE FUNCTION partition_by_month(tbl TEXT, col TEXT) RETURNS VOID AS $$
+DECLARE
+    legacy TEXT := tbl || '_unpartitioned';
+    month DATE;
+    last_month DATE;
+    def RECORD;
+    views TEXT[] := '{}';
+    stmt TEXT;
+BEGIN
+    IF (SELECT relkind FROM pg_class WHERE oid = tbl::regclass) = 'p' THEN
+        RETURN;
+    END IF;
+
+    -- Definitions are saved before renaming, so that they still reference
+    -- tbl rather than legacy table which is dropped with them in the end.
+    FOR def IN
+        SELECT DISTINCT v.oid, v.relname, v.relkind, pg_get_viewdef(v.oid) AS query
+        FROM pg_depend d
+        JOIN pg_rewrite r ON r.oid = d.objid
+        JOIN pg_class v ON v.oid = r.ev_class
+        WHERE d.classid = 'pg_rewrite'::regclass
+            AND d.refobjid = tbl::regclass
+            AND v.oid <> tbl::regclass
+        ORDER BY v.oid
+    LOOP
+        views := views || format(
+            'CREATE %s %I AS %s',
+            CASE def.relkind WHEN 'm' THEN 'MATERIALIZED VIEW' ELSE 'VIEW' END,
+            def.relname, rtrim(def.query, ';')
+        );
+        views := views || ARRAY(
+            SELECT pg_get_indexdef(indexrelid) FROM pg_index WHERE indrelid = def.oid ORDER BY indexrelid
+        );
+    END LOOP;
+
+    EXECUTE format('ALTER TABLE %I RENAME TO %I', tbl, legacy);
+    EXECUTE format('ALTER INDEX %I RENAME TO %I', tbl || '_pkey', legacy || '_pkey');
+    EXECUTE format(
+        'CREATE TABLE %I (LIKE %I INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING STORAGE INCLUDING COMMENTS) '
+        'PARTITION BY RANGE (%I)',
+        tbl, legacy, col
+    );
+    EXECUTE format('ALTER TABLE %I ALTER COLUMN %I SET NOT NULL', tbl, col);
+    EXECUTE format('ALTER TABLE %I ADD PRIMARY KEY (id, %I)', tbl, col);
+
+    EXECUTE format(
+        'SELECT date_trunc(''month'', min(%1$I))::DATE, date_trunc(''month'', max(%1$I))::DATE FROM %2$I',
+        col, legacy
+    ) INTO month, last_month;
+    WHILE month <= last_month LOOP
+        PERFORM create_month_partition(tbl, month);
+        month := (month + INTERVAL '1 month')::DATE;
+    END LOOP;
+
+    EXECUTE format('INSERT INTO %I SELECT * FROM %I', tbl, legacy);
+
+    FOR def IN
+        SELECT indexrelid::regclass::TEXT AS name, pg_get_indexdef(indexrelid) AS ddl
+        FROM pg_index
+        WHERE indrelid = legacy::regclass AND NOT indisprimary
+    LOOP
+        EXECUTE format('DROP INDEX %s', def.name);
+        EXECUTE regexp_replace(def.ddl, ' ON (ONLY )?(\S+\.)?' || legacy || ' ', ' ON \2' || tbl || ' ');
+    END LOOP;
+
+    FOR def IN
+        SELECT c.conname, pg_get_constraintdef(c.oid) AS ddl
+        FROM pg_constraint c
+        JOIN pg_class target ON target.oid = c.confrelid
+        WHERE c.conrelid = legacy::regclass AND c.contype = 'f' AND target.relkind <> 'p'
+    LOOP
+        EXECUTE format('ALTER TABLE %I ADD CONSTRAINT %I %s', tbl, def.conname, def.ddl);
+    END LOOP;
+
+    FOR def IN
+        SELECT conrelid::regclass AS source, conname
+        FROM pg_constraint
+        WHERE confrelid = legacy::regclass AND contype = 'f'
+    LOOP
+        RAISE NOTICE 'dropping foreign key %.% referencing partitioned table %', def.source, def.conname, tbl;
+    END LOOP;
+
+    EXECUTE format('ALTER SEQUENCE %I OWNED BY %I.id', tbl || '_id_seq', tbl);
+    EXECUTE format('DROP TABLE %I CASCADE', legacy);
+    FOREACH stmt IN ARRAY views LOOP
+        EXECUTE stmt;
+    END LOOP;
+    EXECUTE format('ANALYZE %I', tbl);
+END;
+$$ LANGUAGE plpgsql;
+
+-- Payments go first: converting them drops orders.payment_id foreign key, so
+-- that it is not copied over when orders are converted afterwards.
+SELECT partition_by_month('payments', 'timestamp');
+SELECT partition_by_month('orders', 'timestamp');
+
+DROP FUNCTION partition_by_month(TEXT, TEXT);
@@ -66,6 +66,8 @@ func (r iteratorForAssignOrdersCommoditiesAndDishes) Values() ([]interface{}, er
 		r.rows[0].OrderID,
 		r.rows[0].DishID,
 		r.rows[0].CommodityID,
+		r.rows[0].UnitPrice,
+		r.rows[0].Quantity,
 	}, nil
 }
 
@@ -74,7 +76,7 @@ func (r iteratorForAssignOrdersCommoditiesAndDishes) Err() error {
 }
 
 func (q *Queries) AssignOrdersCommoditiesAndDishes(ctx context.Context, arg []AssignOrdersCommoditiesAndDishesParams) (int64, error) {
-	return q.db.CopyFrom(ctx, []string{"orders_composition"}, []string{"order_id", "dish_id", "commodity_id"}, &iteratorForAssignOrdersCommoditiesAndDishes{rows: arg})
+	return q.db.CopyFrom(ctx, []string{"orders_composition"}, []string{"order_id", "dish_id", "commodity_id", "unit_price", "quantity"}, &iteratorForAssignOrdersCommoditiesAndDishes{rows: arg})
 }
 
 // iteratorForCreateCategories implements pgx.CopyFromSource.
@@ -291,6 +293,40 @@ func (q *Queries) CreateDishes(ctx context.Context, arg []CreateDishesParams) (i
 	return q.db.CopyFrom(ctx, []string{"dishes"}, []string{"supplier_id", "name", "cost", "image", "ingredients", "weight", "calories", "allergens", "rating"}, &iteratorForCreateDishes{rows: arg})
 }
 
+// iteratorForCreateOrderStatusHistory implements pgx.CopyFromSource.
+type iteratorForCreateOrderStatusHistory struct {
+	rows                 []CreateOrderStatusHistoryParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCreateOrderStatusHistory) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCreateOrderStatusHistory) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].OrderID,
+		r.rows[0].Status,
+		r.rows[0].Timestamp,
+	}, nil
+}
+
+func (r iteratorForCreateOrderStatusHistory) Err() error {
+	return nil
+}
+
+func (q *Queries) CreateOrderStatusHistory(ctx context.Context, arg []CreateOrderStatusHistoryParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"order_status_history"}, []string{"order_id", "status", "timestamp"}, &iteratorForCreateOrderStatusHistory{rows: arg})
+}
+
 // iteratorForCreateOrders implements pgx.CopyFromSource.
 type iteratorForCreateOrders struct {
 	rows                 []CreateOrdersParams
@@ -68,15 +68,35 @@ type Dish struct {
 }
 
 type Order struct {
-	ID            int32
-	UserID        pgtype.Int4
-	Timestamp     pgtype.Timestamp
-	SourceAddress pgtype.Text
-	TargetAddress pgtype.Text
-	CourierID     pgtype.Int4
-	Status        pgtype.Text
-	PaymentID     pgtype.Int4
-	DiscountID    pgtype.Int4
+	ID             int32
+	UserID         int32
+	Timestamp      pgtype.Timestamp
+	SourceAddress  pgtype.Text
+	TargetAddress  pgtype.Text
+	CourierID      pgtype.Int4
+	Status         string
+	PaymentID      pgtype.Int4
+	DiscountID     pgtype.Int4
+	TotalAmount    pgtype.Int8
+	DiscountAmount pgtype.Int8
+}
+
+type OrderStatusHistory struct {
+	ID        int32
+	OrderID   int32
+	Status    string
+	Timestamp pgtype.Timestamp
+}
+
+type OrderTotal struct {
+	OrderID         int32
+	UserID          int32
+	Timestamp       pgtype.Timestamp
+	Positions       int64
+	Items           int64
+	Total           int64
+	Discount        int64
+	DiscountedTotal int64
 }
 
 type OrdersComposition struct {
@@ -84,6 +104,8 @@ type OrdersComposition struct {
 	OrderID     int32
 	DishID      pgtype.Int4
 	CommodityID pgtype.Int4
+	UnitPrice   int64
+	Quantity    int32
 }
 
 type Payment struct {
@@ -103,11 +125,20 @@ type Supplier struct {
 	Address       string
 }
 
+type SupplierMonthlySale struct {
+	SupplierID         int32
+	Month              pgtype.Timestamp
+	DishesOrdered      int64
+	CommoditiesOrdered int64
+	OrdersCount        int64
+	Revenue            int64
+}
+
 type User struct {
 	ID           int32
 	Name         pgtype.Text
 	Surname      pgtype.Text
-	Email        pgtype.Text
+	Email        string
 	Phone        pgtype.Text
 	PasswordHash pgtype.Text
 }
@@ -123,3 +154,9 @@ type UserCard struct {
 	UserID int32
 	Number string
 }
+
+type UserMonthlyOrder struct {
+	UserID      int32
+	Month       pgtype.Timestamp
+	OrdersCount int64
+}
@@ -21,6 +21,8 @@ type AssignOrdersCommoditiesAndDishesParams struct {
 	OrderID     int32
 	DishID      pgtype.Int4
 	CommodityID pgtype.Int4
+	UnitPrice   int64
+	Quantity    int32
 }
 
 type CreateCommoditiesParams struct {
@@ -65,13 +67,19 @@ type CreateDishesParams struct {
 	Rating      pgtype.Numeric
 }
 
+type CreateOrderStatusHistoryParams struct {
+	OrderID   int32
+	Status    string
+	Timestamp pgtype.Timestamp
+}
+
 type CreateOrdersParams struct {
-	UserID        pgtype.Int4
+	UserID        int32
 	Timestamp     pgtype.Timestamp
 	SourceAddress pgtype.Text
 	TargetAddress pgtype.Text
 	CourierID     pgtype.Int4
-	Status        pgtype.Text
+	Status        string
 	PaymentID     pgtype.Int4
 }
 
@@ -103,11 +111,487 @@ type CreateUserCardsParams struct {
 type CreateUsersParams struct {
 	Name         pgtype.Text
 	Surname      pgtype.Text
-	Email        pgtype.Text
+	Email        string
 	Phone        pgtype.Text
 	PasswordHash pgtype.Text
 }
 
+const createOrder = `-- name: CreateOrder :one
+INSERT INTO orders (user_id, timestamp, source_address, target_address, courier_id, status, payment_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id
+`
+
+type CreateOrderParams struct {
+	UserID        int32
+	Timestamp     pgtype.Timestamp
+	SourceAddress pgtype.Text
+	TargetAddress pgtype.Text
+	CourierID     pgtype.Int4
+	Status        string
+	PaymentID     pgtype.Int4
+}
+
+func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (int32, error) {
+	row := q.db.QueryRow(ctx, createOrder,
+		arg.UserID,
+		arg.Timestamp,
+		arg.SourceAddress,
+		arg.TargetAddress,
+		arg.CourierID,
+		arg.Status,
+		arg.PaymentID,
+	)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createOrderStatus = `-- name: CreateOrderStatus :exec
+INSERT INTO order_status_history (order_id, status, timestamp)
+VALUES ($1, $2, $3)
+`
+
+type CreateOrderStatusParams struct {
+	OrderID   int32
+	Status    string
+	Timestamp pgtype.Timestamp
+}
+
+func (q *Queries) CreateOrderStatus(ctx context.Context, arg CreateOrderStatusParams) error {
+	_, err := q.db.Exec(ctx, createOrderStatus, arg.OrderID, arg.Status, arg.Timestamp)
+	return err
+}
+
+const createPayment = `-- name: CreatePayment :one
+INSERT INTO payments (method, card_id, status)
+VALUES ($1, $2, $3)
+RETURNING id
+`
+
+type CreatePaymentParams struct {
+	Method string
+	CardID pgtype.Int4
+	Status string
+}
+
+func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (int32, error) {
+	row := q.db.QueryRow(ctx, createPayment, arg.Method, arg.CardID, arg.Status)
+	var id int32
+	err := row.Scan(&id)
+	return id, err
+}
+
+const fillOrderAmount = `-- name: FillOrderAmount :exec
+UPDATE orders o
+SET total_amount = COALESCE((
+        SELECT SUM(oc.unit_price * oc.quantity)
+        FROM orders_composition oc
+        WHERE oc.order_id = o.id
+    ), 0),
+    discount_amount = order_discount_amount(o.id)
+WHERE o.id = $1
+`
+
+// Stores amounts of a single order, the same way FillOrderAmounts does.
+func (q *Queries) FillOrderAmount(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, fillOrderAmount, id)
+	return err
+}
+
+const fillOrderAmounts = `-- name: FillOrderAmounts :execrows
+UPDATE orders o
+SET total_amount = COALESCE((
+        SELECT SUM(oc.unit_price * oc.quantity)
+        FROM orders_composition oc
+        WHERE oc.order_id = o.id
+    ), 0),
+    discount_amount = order_discount_amount(o.id)
+WHERE o.total_amount IS NULL
+`
+
+// Stores amounts of orders created without them, using line prices and
+// discounts active at the moment.
+func (q *Queries) FillOrderAmounts(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, fillOrderAmounts)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const listCommodities = `-- name: ListCommodities :many
+SELECT id, supplier_id, name, cost, image, ingredients, weight, rating FROM commodities
+WHERE ($1::INT IS NULL OR supplier_id = $1)
+AND ($2::TEXT IS NULL OR name LIKE $2 || '%')
+AND ($3::TEXT IS NULL OR ingredients ILIKE '%' || $3 || '%')
+AND ($4::BIGINT IS NULL OR cost >= $4)
+AND ($5::BIGINT IS NULL OR cost <= $5)
+AND ($6::NUMERIC IS NULL OR rating >= $6)
+ORDER BY id
+LIMIT $7 OFFSET $8
+`
+
+type ListCommoditiesParams struct {
+	SupplierID pgtype.Int4
+	Name       pgtype.Text
+	Ingredient pgtype.Text
+	MinCost    pgtype.Int8
+	MaxCost    pgtype.Int8
+	MinRating  pgtype.Numeric
+	PageSize   int32
+	PageOffset int32
+}
+
+func (q *Queries) ListCommodities(ctx context.Context, arg ListCommoditiesParams) ([]Commodity, error) {
+	rows, err := q.db.Query(ctx, listCommodities,
+		arg.SupplierID,
+		arg.Name,
+		arg.Ingredient,
+		arg.MinCost,
+		arg.MaxCost,
+		arg.MinRating,
+		arg.PageSize,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Commodity
+	for rows.Next() {
+		var i Commodity
+		if err := rows.Scan(
+			&i.ID,
+			&i.SupplierID,
+			&i.Name,
+			&i.Cost,
+			&i.Image,
+			&i.Ingredients,
+			&i.Weight,
+			&i.Rating,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDishes = `-- name: ListDishes :many
+SELECT id, supplier_id, name, cost, image, ingredients, weight, calories, allergens, rating FROM dishes
+WHERE ($1::INT IS NULL OR supplier_id = $1)
+AND ($2::TEXT IS NULL OR name LIKE $2 || '%')
+AND ($3::TEXT IS NULL OR ingredients ILIKE '%' || $3 || '%')
+AND ($4::BIGINT IS NULL OR cost >= $4)
+AND ($5::BIGINT IS NULL OR cost <= $5)
+AND ($6::NUMERIC IS NULL OR rating >= $6)
+AND ($7::INT IS NULL OR calories <= $7)
+ORDER BY id
+LIMIT $8 OFFSET $9
+`
+
+type ListDishesParams struct {
+	SupplierID  pgtype.Int4
+	Name        pgtype.Text
+	Ingredient  pgtype.Text
+	MinCost     pgtype.Int8
+	MaxCost     pgtype.Int8
+	MinRating   pgtype.Numeric
+	MaxCalories pgtype.Int4
+	PageSize    int32
+	PageOffset  int32
+}
+
+func (q *Queries) ListDishes(ctx context.Context, arg ListDishesParams) ([]Dish, error) {
+	rows, err := q.db.Query(ctx, listDishes,
+		arg.SupplierID,
+		arg.Name,
+		arg.Ingredient,
+		arg.MinCost,
+		arg.MaxCost,
+		arg.MinRating,
+		arg.MaxCalories,
+		arg.PageSize,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Dish
+	for rows.Next() {
+		var i Dish
+		if err := rows.Scan(
+			&i.ID,
+			&i.SupplierID,
+			&i.Name,
+			&i.Cost,
+			&i.Image,
+			&i.Ingredients,
+			&i.Weight,
+			&i.Calories,
+			&i.Allergens,
+			&i.Rating,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrders = `-- name: ListOrders :many
+SELECT id, user_id, timestamp, source_address, target_address, courier_id, status, payment_id, discount_id, total_amount, discount_amount FROM orders
+WHERE ($1::INT IS NULL OR user_id = $1)
+AND ($2::INT IS NULL OR courier_id = $2)
+AND ($3::TEXT IS NULL OR status = $3)
+AND ($4::TIMESTAMP IS NULL OR timestamp >= $4)
+AND ($5::TIMESTAMP IS NULL OR timestamp < $5)
+ORDER BY timestamp DESC, id DESC
+LIMIT $6 OFFSET $7
+`
+
+type ListOrdersParams struct {
+	UserID     pgtype.Int4
+	CourierID  pgtype.Int4
+	Status     pgtype.Text
+	Since      pgtype.Timestamp
+	Until      pgtype.Timestamp
+	PageSize   int32
+	PageOffset int32
+}
+
+// Orders from the most recent, timestamps are filtered by [since, until).
+func (q *Queries) ListOrders(ctx context.Context, arg ListOrdersParams) ([]Order, error) {
+	rows, err := q.db.Query(ctx, listOrders,
+		arg.UserID,
+		arg.CourierID,
+		arg.Status,
+		arg.Since,
+		arg.Until,
+		arg.PageSize,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Order
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Timestamp,
+			&i.SourceAddress,
+			&i.TargetAddress,
+			&i.CourierID,
+			&i.Status,
+			&i.PaymentID,
+			&i.DiscountID,
+			&i.TotalAmount,
+			&i.DiscountAmount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSuppliers = `-- name: ListSuppliers :many
+SELECT id, name, work_time_start, work_time_end, rating, address FROM suppliers
+WHERE ($1::TEXT IS NULL OR name ILIKE '%' || $1 || '%')
+ORDER BY id
+LIMIT $2 OFFSET $3
+`
+
+type ListSuppliersParams struct {
+	Name       pgtype.Text
+	PageSize   int32
+	PageOffset int32
+}
+
+func (q *Queries) ListSuppliers(ctx context.Context, arg ListSuppliersParams) ([]Supplier, error) {
+	rows, err := q.db.Query(ctx, listSuppliers, arg.Name, arg.PageSize, arg.PageOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Supplier
+	for rows.Next() {
+		var i Supplier
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.WorkTimeStart,
+			&i.WorkTimeEnd,
+			&i.Rating,
+			&i.Address,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsers = `-- name: ListUsers :many
+SELECT id, name, surname, email, phone FROM users
+WHERE ($1::TEXT IS NULL OR surname LIKE $1 || '%')
+ORDER BY id
+LIMIT $2 OFFSET $3
+`
+
+type ListUsersParams struct {
+	Surname    pgtype.Text
+	PageSize   int32
+	PageOffset int32
+}
+
+type ListUsersRow struct {
+	ID      int32
+	Name    pgtype.Text
+	Surname pgtype.Text
+	Email   string
+	Phone   pgtype.Text
+}
+
+// Users without password hashes, optionally filtered by surname prefix.
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]ListUsersRow, error) {
+	rows, err := q.db.Query(ctx, listUsers, arg.Surname, arg.PageSize, arg.PageOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUsersRow
+	for rows.Next() {
+		var i ListUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Surname,
+			&i.Email,
+			&i.Phone,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchDishesByIngredient = `-- name: SearchDishesByIngredient :many
+SELECT id, supplier_id, name, cost, rating FROM dishes
+WHERE ingredients ILIKE $1
+ORDER BY rating DESC, id
+LIMIT $2
+`
+
+type SearchDishesByIngredientParams struct {
+	Pattern    pgtype.Text
+	MaxResults int32
+}
+
+type SearchDishesByIngredientRow struct {
+	ID         int32
+	SupplierID int32
+	Name       string
+	Cost       int64
+	Rating     pgtype.Numeric
+}
+
+func (q *Queries) SearchDishesByIngredient(ctx context.Context, arg SearchDishesByIngredientParams) ([]SearchDishesByIngredientRow, error) {
+	rows, err := q.db.Query(ctx, searchDishesByIngredient, arg.Pattern, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchDishesByIngredientRow
+	for rows.Next() {
+		var i SearchDishesByIngredientRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SupplierID,
+			&i.Name,
+			&i.Cost,
+			&i.Rating,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const selectApplicableDiscounts = `-- name: SelectApplicableDiscounts :many
+SELECT ds.id, ds.type, ds.terms, dt.dish_id, dt.commodity_id
+FROM discounts ds
+LEFT JOIN discount_to_targets dt ON ds.id = dt.discount_id
+WHERE ds.active
+AND (
+    dt.dish_id IN (SELECT oc.dish_id FROM orders_composition oc WHERE oc.order_id = $1)
+    OR dt.commodity_id IN (SELECT oc.commodity_id FROM orders_composition oc WHERE oc.order_id = $1)
+    OR (dt.id IS NULL AND ds.id = (SELECT o.discount_id FROM orders o WHERE o.id = $1))
+)
+ORDER BY ds.id
+`
+
+type SelectApplicableDiscountsRow struct {
+	ID          int32
+	Type        string
+	Terms       []byte
+	DishID      pgtype.Int4
+	CommodityID pgtype.Int4
+}
+
+// Active discounts targeting items of the order, and discount attached to
+// the order itself if it has no targets (target columns are NULL then).
+func (q *Queries) SelectApplicableDiscounts(ctx context.Context, orderID int32) ([]SelectApplicableDiscountsRow, error) {
+	rows, err := q.db.Query(ctx, selectApplicableDiscounts, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SelectApplicableDiscountsRow
+	for rows.Next() {
+		var i SelectApplicableDiscountsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Terms,
+			&i.DishID,
+			&i.CommodityID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const selectCategoryIDs = `-- name: SelectCategoryIDs :many
 SELECT id FROM categories
 `
@@ -132,6 +616,58 @@ func (q *Queries) SelectCategoryIDs(ctx context.Context) ([]int32, error) {
 	return items, nil
 }
 
+const selectCommodity = `-- name: SelectCommodity :one
+SELECT id, supplier_id, name, cost, image, ingredients, weight, rating FROM commodities WHERE id = $1
+`
+
+func (q *Queries) SelectCommodity(ctx context.Context, id int32) (Commodity, error) {
+	row := q.db.QueryRow(ctx, selectCommodity, id)
+	var i Commodity
+	err := row.Scan(
+		&i.ID,
+		&i.SupplierID,
+		&i.Name,
+		&i.Cost,
+		&i.Image,
+		&i.Ingredients,
+		&i.Weight,
+		&i.Rating,
+	)
+	return i, err
+}
+
+const selectCommodityCosts = `-- name: SelectCommodityCosts :many
+SELECT id, cost FROM commodities
+`
+
+type SelectCommodityCostsRow struct {
+	ID   int32
+	Cost int64
+}
+
+func (q *Queries) SelectCommodityCosts(ctx context.Context) ([]SelectCommodityCostsRow, error) {
+	rows, err := q.db.Query(ctx, selectCommodityCosts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SelectCommodityCostsRow
+	for rows.Next() {
+		var i SelectCommodityCostsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Cost,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const selectCommodityIDs = `-- name: SelectCommodityIDs :many
 SELECT id FROM commodities
 `
@@ -156,6 +692,63 @@ func (q *Queries) SelectCommodityIDs(ctx context.Context) ([]int32, error) {
 	return items, nil
 }
 
+const selectCourier = `-- name: SelectCourier :one
+SELECT id, name, phone, rating FROM couriers WHERE id = $1
+`
+
+func (q *Queries) SelectCourier(ctx context.Context, id int32) (Courier, error) {
+	row := q.db.QueryRow(ctx, selectCourier, id)
+	var i Courier
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Phone,
+		&i.Rating,
+	)
+	return i, err
+}
+
+const selectCourierActiveOrders = `-- name: SelectCourierActiveOrders :many
+SELECT id, timestamp, source_address, target_address, status FROM orders
+WHERE courier_id = $1 AND status IN ('accepted', 'picked_up')
+ORDER BY timestamp
+`
+
+type SelectCourierActiveOrdersRow struct {
+	ID            int32
+	Timestamp     pgtype.Timestamp
+	SourceAddress pgtype.Text
+	TargetAddress pgtype.Text
+	Status        string
+}
+
+// Orders accepted by courier which are not delivered yet.
+func (q *Queries) SelectCourierActiveOrders(ctx context.Context, courierID pgtype.Int4) ([]SelectCourierActiveOrdersRow, error) {
+	rows, err := q.db.Query(ctx, selectCourierActiveOrders, courierID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SelectCourierActiveOrdersRow
+	for rows.Next() {
+		var i SelectCourierActiveOrdersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Timestamp,
+			&i.SourceAddress,
+			&i.TargetAddress,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const selectCourierIDs = `-- name: SelectCourierIDs :many
 SELECT id FROM couriers
 `
@@ -204,6 +797,60 @@ func (q *Queries) SelectDiscountIDs(ctx context.Context) ([]int32, error) {
 	return items, nil
 }
 
+const selectDish = `-- name: SelectDish :one
+SELECT id, supplier_id, name, cost, image, ingredients, weight, calories, allergens, rating FROM dishes WHERE id = $1
+`
+
+func (q *Queries) SelectDish(ctx context.Context, id int32) (Dish, error) {
+	row := q.db.QueryRow(ctx, selectDish, id)
+	var i Dish
+	err := row.Scan(
+		&i.ID,
+		&i.SupplierID,
+		&i.Name,
+		&i.Cost,
+		&i.Image,
+		&i.Ingredients,
+		&i.Weight,
+		&i.Calories,
+		&i.Allergens,
+		&i.Rating,
+	)
+	return i, err
+}
+
+const selectDishCosts = `-- name: SelectDishCosts :many
+SELECT id, cost FROM dishes
+`
+
+type SelectDishCostsRow struct {
+	ID   int32
+	Cost int64
+}
+
+func (q *Queries) SelectDishCosts(ctx context.Context) ([]SelectDishCostsRow, error) {
+	rows, err := q.db.Query(ctx, selectDishCosts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SelectDishCostsRow
+	for rows.Next() {
+		var i SelectDishCostsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Cost,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const selectDishIDs = `-- name: SelectDishIDs :many
 SELECT id FROM dishes
 `
@@ -228,6 +875,29 @@ func (q *Queries) SelectDishIDs(ctx context.Context) ([]int32, error) {
 	return items, nil
 }
 
+const selectOrder = `-- name: SelectOrder :one
+SELECT id, user_id, timestamp, source_address, target_address, courier_id, status, payment_id, discount_id, total_amount, discount_amount FROM orders WHERE id = $1
+`
+
+func (q *Queries) SelectOrder(ctx context.Context, id int32) (Order, error) {
+	row := q.db.QueryRow(ctx, selectOrder, id)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Timestamp,
+		&i.SourceAddress,
+		&i.TargetAddress,
+		&i.CourierID,
+		&i.Status,
+		&i.PaymentID,
+		&i.DiscountID,
+		&i.TotalAmount,
+		&i.DiscountAmount,
+	)
+	return i, err
+}
+
 const selectOrderIDs = `-- name: SelectOrderIDs :many
 SELECT id FROM orders
 `
@@ -252,6 +922,166 @@ func (q *Queries) SelectOrderIDs(ctx context.Context) ([]int32, error) {
 	return items, nil
 }
 
+const selectOrderLines = `-- name: SelectOrderLines :many
+SELECT oc.id, oc.dish_id, oc.commodity_id, oc.unit_price, oc.quantity
+FROM orders_composition oc
+WHERE oc.order_id = $1
+ORDER BY oc.id
+`
+
+type SelectOrderLinesRow struct {
+	ID          int32
+	DishID      pgtype.Int4
+	CommodityID pgtype.Int4
+	UnitPrice   int64
+	Quantity    int32
+}
+
+func (q *Queries) SelectOrderLines(ctx context.Context, orderID int32) ([]SelectOrderLinesRow, error) {
+	rows, err := q.db.Query(ctx, selectOrderLines, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SelectOrderLinesRow
+	for rows.Next() {
+		var i SelectOrderLinesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.DishID,
+			&i.CommodityID,
+			&i.UnitPrice,
+			&i.Quantity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const selectOrderStatus = `-- name: SelectOrderStatus :one
+SELECT status FROM orders WHERE id = $1
+`
+
+func (q *Queries) SelectOrderStatus(ctx context.Context, id int32) (string, error) {
+	row := q.db.QueryRow(ctx, selectOrderStatus, id)
+	var status string
+	err := row.Scan(&status)
+	return status, err
+}
+
+const selectOrderStatusForUpdate = `-- name: SelectOrderStatusForUpdate :one
+SELECT status FROM orders WHERE id = $1 FOR UPDATE
+`
+
+func (q *Queries) SelectOrderStatusForUpdate(ctx context.Context, id int32) (string, error) {
+	row := q.db.QueryRow(ctx, selectOrderStatusForUpdate, id)
+	var status string
+	err := row.Scan(&status)
+	return status, err
+}
+
+const selectOrderStatusHistory = `-- name: SelectOrderStatusHistory :many
+SELECT id, order_id, status, timestamp FROM order_status_history
+WHERE order_id = $1
+ORDER BY timestamp, id
+`
+
+func (q *Queries) SelectOrderStatusHistory(ctx context.Context, orderID int32) ([]OrderStatusHistory, error) {
+	rows, err := q.db.Query(ctx, selectOrderStatusHistory, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OrderStatusHistory
+	for rows.Next() {
+		var i OrderStatusHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.Status,
+			&i.Timestamp,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const selectOrderTimestamps = `-- name: SelectOrderTimestamps :many
+SELECT id, timestamp, payment_id FROM orders
+`
+
+type SelectOrderTimestampsRow struct {
+	ID        int32
+	Timestamp pgtype.Timestamp
+	PaymentID pgtype.Int4
+}
+
+func (q *Queries) SelectOrderTimestamps(ctx context.Context) ([]SelectOrderTimestampsRow, error) {
+	rows, err := q.db.Query(ctx, selectOrderTimestamps)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SelectOrderTimestampsRow
+	for rows.Next() {
+		var i SelectOrderTimestampsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Timestamp,
+			&i.PaymentID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const selectPaymentCards = `-- name: SelectPaymentCards :many
+SELECT id, card_id FROM payments
+`
+
+type SelectPaymentCardsRow struct {
+	ID     int32
+	CardID pgtype.Int4
+}
+
+func (q *Queries) SelectPaymentCards(ctx context.Context) ([]SelectPaymentCardsRow, error) {
+	rows, err := q.db.Query(ctx, selectPaymentCards)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SelectPaymentCardsRow
+	for rows.Next() {
+		var i SelectPaymentCardsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CardID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const selectPaymentIDs = `-- name: SelectPaymentIDs :many
 SELECT id FROM payments
 `
@@ -276,6 +1106,128 @@ func (q *Queries) SelectPaymentIDs(ctx context.Context) ([]int32, error) {
 	return items, nil
 }
 
+const selectPaymentStatuses = `-- name: SelectPaymentStatuses :many
+SELECT id, status FROM payments
+`
+
+type SelectPaymentStatusesRow struct {
+	ID     int32
+	Status string
+}
+
+func (q *Queries) SelectPaymentStatuses(ctx context.Context) ([]SelectPaymentStatusesRow, error) {
+	rows, err := q.db.Query(ctx, selectPaymentStatuses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SelectPaymentStatusesRow
+	for rows.Next() {
+		var i SelectPaymentStatusesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const selectSupplier = `-- name: SelectSupplier :one
+SELECT id, name, work_time_start, work_time_end, rating, address FROM suppliers WHERE id = $1
+`
+
+func (q *Queries) SelectSupplier(ctx context.Context, id int32) (Supplier, error) {
+	row := q.db.QueryRow(ctx, selectSupplier, id)
+	var i Supplier
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.WorkTimeStart,
+		&i.WorkTimeEnd,
+		&i.Rating,
+		&i.Address,
+	)
+	return i, err
+}
+
+const selectSupplierCommodities = `-- name: SelectSupplierCommodities :many
+SELECT id, supplier_id, name, cost, image, ingredients, weight, rating FROM commodities
+WHERE supplier_id = $1
+ORDER BY rating DESC, id
+`
+
+func (q *Queries) SelectSupplierCommodities(ctx context.Context, supplierID int32) ([]Commodity, error) {
+	rows, err := q.db.Query(ctx, selectSupplierCommodities, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Commodity
+	for rows.Next() {
+		var i Commodity
+		if err := rows.Scan(
+			&i.ID,
+			&i.SupplierID,
+			&i.Name,
+			&i.Cost,
+			&i.Image,
+			&i.Ingredients,
+			&i.Weight,
+			&i.Rating,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const selectSupplierDishes = `-- name: SelectSupplierDishes :many
+SELECT id, supplier_id, name, cost, image, ingredients, weight, calories, allergens, rating FROM dishes
+WHERE supplier_id = $1
+ORDER BY rating DESC, id
+`
+
+func (q *Queries) SelectSupplierDishes(ctx context.Context, supplierID int32) ([]Dish, error) {
+	rows, err := q.db.Query(ctx, selectSupplierDishes, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Dish
+	for rows.Next() {
+		var i Dish
+		if err := rows.Scan(
+			&i.ID,
+			&i.SupplierID,
+			&i.Name,
+			&i.Cost,
+			&i.Image,
+			&i.Ingredients,
+			&i.Weight,
+			&i.Calories,
+			&i.Allergens,
+			&i.Rating,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const selectSupplierIDs = `-- name: SelectSupplierIDs :many
 SELECT id FROM suppliers
 `
@@ -300,6 +1252,31 @@ func (q *Queries) SelectSupplierIDs(ctx context.Context) ([]int32, error) {
 	return items, nil
 }
 
+const selectUser = `-- name: SelectUser :one
+SELECT id, name, surname, email, phone FROM users WHERE id = $1
+`
+
+type SelectUserRow struct {
+	ID      int32
+	Name    pgtype.Text
+	Surname pgtype.Text
+	Email   string
+	Phone   pgtype.Text
+}
+
+func (q *Queries) SelectUser(ctx context.Context, id int32) (SelectUserRow, error) {
+	row := q.db.QueryRow(ctx, selectUser, id)
+	var i SelectUserRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Surname,
+		&i.Email,
+		&i.Phone,
+	)
+	return i, err
+}
+
 const selectUserCardIDs = `-- name: SelectUserCardIDs :many
 SELECT id FROM user_cards
 `
@@ -324,6 +1301,38 @@ func (q *Queries) SelectUserCardIDs(ctx context.Context) ([]int32, error) {
 	return items, nil
 }
 
+const selectUserCardOwners = `-- name: SelectUserCardOwners :many
+SELECT id, user_id FROM user_cards
+`
+
+type SelectUserCardOwnersRow struct {
+	ID     int32
+	UserID int32
+}
+
+func (q *Queries) SelectUserCardOwners(ctx context.Context) ([]SelectUserCardOwnersRow, error) {
+	rows, err := q.db.Query(ctx, selectUserCardOwners)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SelectUserCardOwnersRow
+	for rows.Next() {
+		var i SelectUserCardOwnersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const selectUserIDs = `-- name: SelectUserIDs :many
 SELECT id FROM users
 `
@@ -347,3 +1356,37 @@ func (q *Queries) SelectUserIDs(ctx context.Context) ([]int32, error) {
 	}
 	return items, nil
 }
+
+const syncOrderStatuses = `-- name: SyncOrderStatuses :execrows
+UPDATE orders o
+SET status = h.status
+FROM (
+    SELECT DISTINCT ON (order_id) order_id, status
+    FROM order_status_history
+    ORDER BY order_id, timestamp DESC, id DESC
+) h
+WHERE o.id = h.order_id AND o.status <> h.status
+`
+
+// Sets status of every order to the latest one from its history.
+func (q *Queries) SyncOrderStatuses(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, syncOrderStatuses)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateOrderStatus = `-- name: UpdateOrderStatus :exec
+UPDATE orders SET status = $1 WHERE id = $2
+`
+
+type UpdateOrderStatusParams struct {
+	Status string
+	ID     int32
+}
+
+func (q *Queries) UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) error {
+	_, err := q.db.Exec(ctx, updateOrderStatus, arg.Status, arg.ID)
+	return err
+}
@@ -0,0 +1,139 @@
+package queries_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+	"github.com/LeKSuS-04/mephi-db/internal/fixtures"
+	"github.com/LeKSuS-04/mephi-db/internal/pricing"
+	"github.com/LeKSuS-04/mephi-db/internal/tempdb/tempdbtest"
+)
+
+// loadSeed loads seed fixture in a transaction which is rolled back when t
+// finishes.
+func loadSeed(t *testing.T, pool *pgxpool.Pool) pgx.Tx {
+	t.Helper()
+	ctx := context.Background()
+
+	fs, err := fixtures.Find([]string{"seed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	t.Cleanup(func() { tx.Rollback(ctx) })
+	if err := fixtures.Load(ctx, tx, fs[0]); err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestFillOrderAmounts(t *testing.T) {
+	server := tempdbtest.Start(t, "../../../sql/schema.sql")
+	ctx := context.Background()
+	tx := loadSeed(t, server.Pool)
+	q := queries.New(tx)
+
+	orderIDs, err := q.SelectOrderIDs(ctx)
+	if err != nil {
+		t.Fatalf("select order ids: %v", err)
+	}
+	if _, err := tx.Exec(ctx, "UPDATE orders SET total_amount = NULL, discount_amount = NULL"); err != nil {
+		t.Fatalf("reset amounts: %v", err)
+	}
+
+	n, err := q.FillOrderAmounts(ctx)
+	if err != nil {
+		t.Fatalf("fill order amounts: %v", err)
+	}
+	if n != int64(len(orderIDs)) {
+		t.Errorf("filled %d orders, want %d", n, len(orderIDs))
+	}
+	for _, id := range orderIDs {
+		want, err := pricing.OrderPrice(ctx, q, id)
+		if err != nil {
+			t.Fatalf("price of order %d: %v", id, err)
+		}
+		var total, discount int64
+		err = tx.QueryRow(ctx, "SELECT total_amount, discount_amount FROM orders WHERE id = $1", id).Scan(&total, &discount)
+		if err != nil {
+			t.Fatalf("select amounts of order %d: %v", id, err)
+		}
+		if total != want.Subtotal || discount != want.Discount {
+			t.Errorf("order %d: stored total %d and discount %d, want %d and %d", id, total, discount, want.Subtotal, want.Discount)
+		}
+	}
+
+	// Orders already having amounts are left intact.
+	if n, err := q.FillOrderAmounts(ctx); err != nil || n != 0 {
+		t.Errorf("second fill updated %d orders with error %v, want none", n, err)
+	}
+}
+
+func TestSyncOrderStatuses(t *testing.T) {
+	server := tempdbtest.Start(t, "../../../sql/schema.sql")
+	ctx := context.Background()
+	tx := loadSeed(t, server.Pool)
+	q := queries.New(tx)
+
+	if _, err := tx.Exec(ctx, "TRUNCATE order_status_history"); err != nil {
+		t.Fatalf("truncate history: %v", err)
+	}
+	if _, err := tx.Exec(ctx, "UPDATE orders SET status = 'created'"); err != nil {
+		t.Fatalf("reset statuses: %v", err)
+	}
+	orderIDs, err := q.SelectOrderIDs(ctx)
+	if err != nil {
+		t.Fatalf("select order ids: %v", err)
+	}
+	if len(orderIDs) < 3 {
+		t.Fatalf("seed has %d orders, want at least 3", len(orderIDs))
+	}
+
+	at := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	history := []struct {
+		orderID int32
+		status  string
+		at      time.Time
+	}{
+		// Latest entry wins regardless of insertion order.
+		{orderIDs[0], "paid", at.Add(time.Minute)},
+		{orderIDs[0], "created", at},
+		// Entries with equal timestamps are ordered by id.
+		{orderIDs[1], "paid", at},
+		{orderIDs[1], "canceled", at},
+		// Status equal to the current one is not an update.
+		{orderIDs[2], "created", at},
+	}
+	for _, h := range history {
+		_, err := tx.Exec(ctx, "INSERT INTO order_status_history (order_id, status, timestamp) VALUES ($1, $2, $3)", h.orderID, h.status, h.at)
+		if err != nil {
+			t.Fatalf("insert history: %v", err)
+		}
+	}
+
+	n, err := q.SyncOrderStatuses(ctx)
+	if err != nil {
+		t.Fatalf("sync order statuses: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("updated %d orders, want 2", n)
+	}
+	want := map[int32]string{orderIDs[0]: "paid", orderIDs[1]: "canceled", orderIDs[2]: "created"}
+	for id, status := range want {
+		var got string
+		if err := tx.QueryRow(ctx, "SELECT status FROM orders WHERE id = $1", id).Scan(&got); err != nil {
+			t.Fatalf("select status of order %d: %v", id, err)
+		}
+		if got != status {
+			t.Errorf("order %d has status %q, want %q", id, got, status)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package equiv
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+	"github.com/LeKSuS-04/mephi-db/internal/fixtures"
+	"github.com/LeKSuS-04/mephi-db/internal/partitions"
+	"github.com/LeKSuS-04/mephi-db/internal/reports"
+)
+
+// Current is name of dataset already present in the database.
+const Current = "current"
+
+// Query is a single formulation to be compared.
+type Query struct {
+	Name string
+	SQL  string
+	Args []any
+	// Simple makes query be sent using simple protocol, so that textual
+	// arguments are coerced to parameter types by postgres.
+	Simple bool
+}
+
+// FromFile reads query from file. Arguments are substituted into $1, $2,
+// etc. placeholders as literals.
+func FromFile(path string, args []string) (Query, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Query{}, fmt.Errorf("read query: %w", err)
+	}
+	q := Query{
+		Name:   path,
+		SQL:    strings.TrimRight(strings.TrimSpace(string(data)), ";"),
+		Simple: true,
+	}
+	for _, arg := range args {
+		q.Args = append(q.Args, arg)
+	}
+	return q, nil
+}
+
+// FromReport takes query of a catalog report.
+func FromReport(name string, params map[string]string) (Query, error) {
+	r, err := reports.Find(name)
+	if err != nil {
+		return Query{}, err
+	}
+	args, err := r.ParseArgs(r.SelectParams(params))
+	if err != nil {
+		return Query{}, fmt.Errorf("parse arguments of %q: %w", name, err)
+	}
+	stmt, err := r.Statement(args)
+	if err != nil {
+		return Query{}, fmt.Errorf("build statement of %q: %w", name, err)
+	}
+	return Query{Name: name, SQL: stmt.SQL, Args: stmt.Args}, nil
+}
+
+// Row is a distinct result row together with amount of its occurrences.
+type Row struct {
+	Values []any `json:"values"`
+	Count  int   `json:"count"`
+}
+
+// Diff is a multiset difference of two results on one dataset.
+type Diff struct {
+	Dataset  string   `json:"dataset"`
+	ColumnsA []string `json:"columns_a"`
+	ColumnsB []string `json:"columns_b"`
+	RowsA    int      `json:"rows_a"`
+	RowsB    int      `json:"rows_b"`
+	// Mismatch describes why results are not comparable at all, e.g.
+	// different amount of columns.
+	Mismatch string `json:"mismatch,omitempty"`
+	// OnlyInA holds rows returned by the first query more times than by the
+	// second one, with Count being the excess. OnlyInB is the opposite.
+	OnlyInA []Row `json:"only_in_a,omitempty"`
+	OnlyInB []Row `json:"only_in_b,omitempty"`
+}
+
+func (d Diff) Equal() bool {
+	return d.Mismatch == "" && len(d.OnlyInA) == 0 && len(d.OnlyInB) == 0
+}
+
+// Verify compares queries on current dataset and on each fixture. Fixtures
+// are loaded in transactions which are rolled back, leaving current data
+// intact; loading truncates all tables, so it locks them until comparison
+// is done.
+func Verify(ctx context.Context, pg *pgxpool.Pool, a, b Query, fs []fixtures.Fixture, withCurrent bool) ([]Diff, error) {
+	var diffs []Diff
+	if withCurrent {
+		diff, err := Compare(ctx, pg, a, b)
+		if err != nil {
+			return nil, fmt.Errorf("compare on %s dataset: %w", Current, err)
+		}
+		diff.Dataset = Current
+		diffs = append(diffs, *diff)
+	}
+	if len(fs) == 0 {
+		return diffs, nil
+	}
+
+	partitioned, err := partitions.Enabled(ctx, pg)
+	if err != nil {
+		return nil, fmt.Errorf("check partitioning: %w", err)
+	}
+	if partitioned {
+		if err := partitions.EnsureRange(ctx, pg, fixtures.Since, time.Now()); err != nil {
+			return nil, fmt.Errorf("ensure partitions: %w", err)
+		}
+	}
+
+	for _, f := range fs {
+		var diff *Diff
+		err := pgx.BeginFunc(ctx, pg, func(tx pgx.Tx) error {
+			if err := fixtures.Load(ctx, tx, f); err != nil {
+				return err
+			}
+			var err error
+			diff, err = Compare(ctx, tx, a, b)
+			if err != nil {
+				return err
+			}
+			return errRollback
+		})
+		if !errors.Is(err, errRollback) {
+			return nil, fmt.Errorf("compare on %s fixture: %w", f.Name, err)
+		}
+		diff.Dataset = f.Name
+		diffs = append(diffs, *diff)
+	}
+	return diffs, nil
+}
+
+var errRollback = errors.New("rollback")
+
+// Compare runs both queries and diffs their results as multisets, ignoring
+// row order. Columns are matched by position, their names are not compared.
+func Compare(ctx context.Context, db queries.DBTX, a, b Query) (*Diff, error) {
+	columnsA, rowsA, err := fetch(ctx, db, a)
+	if err != nil {
+		return nil, fmt.Errorf("run %s: %w", a.Name, err)
+	}
+	columnsB, rowsB, err := fetch(ctx, db, b)
+	if err != nil {
+		return nil, fmt.Errorf("run %s: %w", b.Name, err)
+	}
+
+	diff := &Diff{
+		ColumnsA: columnsA,
+		ColumnsB: columnsB,
+		RowsA:    countRows(rowsA),
+		RowsB:    countRows(rowsB),
+	}
+	if len(columnsA) != len(columnsB) {
+		diff.Mismatch = fmt.Sprintf("queries return %d and %d columns", len(columnsA), len(columnsB))
+		return diff, nil
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(rowsA)) {
+		row := rowsA[key]
+		if excess := row.Count - rowsB[key].Count; excess > 0 {
+			diff.OnlyInA = append(diff.OnlyInA, Row{Values: row.Values, Count: excess})
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(rowsB)) {
+		row := rowsB[key]
+		if excess := row.Count - rowsA[key].Count; excess > 0 {
+			diff.OnlyInB = append(diff.OnlyInB, Row{Values: row.Values, Count: excess})
+		}
+	}
+	return diff, nil
+}
+
+// fetch runs query and groups equal rows, keyed by their JSON encoding.
+func fetch(ctx context.Context, db queries.DBTX, q Query) ([]string, map[string]Row, error) {
+	args := q.Args
+	if q.Simple {
+		args = append([]any{pgx.QueryExecModeSimpleProtocol}, args...)
+	}
+	rows, err := db.Query(ctx, q.SQL, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	fields := rows.FieldDescriptions()
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		columns[i] = f.Name
+	}
+
+	grouped := make(map[string]Row)
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return nil, nil, err
+		}
+		for i, value := range values {
+			if values[i], err = plainValue(value); err != nil {
+				return nil, nil, fmt.Errorf("convert column %q: %w", columns[i], err)
+			}
+		}
+		key, err := json.Marshal(values)
+		if err != nil {
+			return nil, nil, err
+		}
+		row := grouped[string(key)]
+		row.Values = values
+		row.Count++
+		grouped[string(key)] = row
+	}
+	return columns, grouped, rows.Err()
+}
+
+func plainValue(value any) (any, error) {
+	switch v := value.(type) {
+	case driver.Valuer:
+		return v.Value()
+	case []byte:
+		return `\x` + hex.EncodeToString(v), nil
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano), nil
+	default:
+		return v, nil
+	}
+}
+
+func countRows(rows map[string]Row) int {
+	n := 0
+	for _, row := range rows {
+		n += row.Count
+	}
+	return n
+}
@@ -0,0 +1,35 @@
+package equiv_test
+
+import (
+	"testing"
+
+	"github.com/LeKSuS-04/mephi-db/internal/equiv"
+	"github.com/LeKSuS-04/mephi-db/internal/equiv/equivtest"
+	"github.com/LeKSuS-04/mephi-db/internal/tempdb/tempdbtest"
+)
+
+// TestReportFormulations checks that alternative formulations of the same
+// catalog report return the same rows on every fixture.
+func TestReportFormulations(t *testing.T) {
+	server := tempdbtest.Start(t, "../../sql/schema.sql")
+
+	pairs := [][2]string{
+		{"unordered_supplier_dishes", "unordered_supplier_dishes_except"},
+		{"unordered_supplier_dishes", "unordered_supplier_dishes_count"},
+		{"users_without_restaurant_orders", "users_without_restaurant_orders_count"},
+		{"users_never_paid_by_card", "users_never_paid_by_card_except"},
+	}
+	for _, pair := range pairs {
+		t.Run(pair[0]+"/"+pair[1], func(t *testing.T) {
+			a, err := equiv.FromReport(pair[0], nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := equiv.FromReport(pair[1], nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			equivtest.AssertEquivalent(t, server.Pool, a, b)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Package equivtest provides test helpers built on equiv, kept apart so that
+// equiv itself does not import testing.
+package equivtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/LeKSuS-04/mephi-db/internal/equiv"
+	"github.com/LeKSuS-04/mephi-db/internal/fixtures"
+)
+
+// AssertEquivalent is a test helper failing tb when queries return different
+// results on any fixture. Current dataset is not used, since its contents
+// are unknown to tests.
+func AssertEquivalent(tb testing.TB, pg *pgxpool.Pool, a, b equiv.Query) {
+	tb.Helper()
+
+	fs, err := fixtures.All()
+	if err != nil {
+		tb.Fatalf("load fixtures: %v", err)
+	}
+	diffs, err := equiv.Verify(context.Background(), pg, a, b, fs, false)
+	if err != nil {
+		tb.Fatalf("verify %s and %s: %v", a.Name, b.Name, err)
+	}
+	for _, diff := range diffs {
+		if diff.Equal() {
+			continue
+		}
+		if diff.Mismatch != "" {
+			tb.Errorf("%s fixture: %s", diff.Dataset, diff.Mismatch)
+			continue
+		}
+		tb.Errorf("%s fixture: %s and %s differ: %d rows only in first, %d only in second",
+			diff.Dataset, a.Name, b.Name, len(diff.OnlyInA), len(diff.OnlyInB))
+		for _, row := range diff.OnlyInA {
+			tb.Logf("  - %v (x%d)", row.Values, row.Count)
+		}
+		for _, row := range diff.OnlyInB {
+			tb.Logf("  + %v (x%d)", row.Values, row.Count)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+-- Rows which are equal in every column except id: namesakes, namesake
+-- dishes ordered within one order and repeated orders of one user. Several
+-- units of the same dish are a single position with quantity. Catches
+-- formulations differing in DISTINCT, set operations or counting positions
+-- instead of units.
+
+INSERT INTO users (name, surname, email, phone, password_hash) VALUES
+    ('Anna', 'Smirnova', 'anna1@example.com', '+7 900 000-00-01', 'hash'),
+    ('Anna', 'Smirnova', 'anna2@example.com', '+7 900 000-00-01', 'hash');
+
+INSERT INTO user_cards (user_id, number) VALUES
+    (1, '4000 0000 0000 0001'),
+    (1, '4000 0000 0000 0001');
+
+INSERT INTO payments (method, card_id, timestamp, status) VALUES
+    ('card', 1, date_trunc('day', now()) - INTERVAL '3 days' + INTERVAL '12 hours', 'successful'),
+    ('card', 2, date_trunc('day', now()) - INTERVAL '3 days' + INTERVAL '12 hours', 'successful');
+
+INSERT INTO couriers (name, phone, rating) VALUES
+    ('Courier', '+7 900 000-00-10', 4.50),
+    ('Courier', '+7 900 000-00-10', 4.50);
+
+INSERT INTO suppliers (name, work_time_start, work_time_end, rating, address) VALUES
+    ('Restaurant', '09:00', '21:00', 4.80, 'Moscow, Tverskaya, 1');
+
+INSERT INTO dishes (supplier_id, name, cost, image, ingredients, weight, calories, allergens, rating) VALUES
+    (1, 'Borsch', 35000, NULL, 'beet, cabbage, meat', 400, 250, 'none', 4.90),
+    (1, 'Borsch', 35000, NULL, 'beet, cabbage, meat', 400, 250, 'none', 4.90);
+
+INSERT INTO commodities (supplier_id, name, cost, image, ingredients, weight, rating) VALUES
+    (1, 'Bread', 6000, NULL, 'flour, water', 500, 4.00);
+
+INSERT INTO orders (user_id, timestamp, source_address, target_address, courier_id, status, payment_id, discount_id) VALUES
+    (1, date_trunc('day', now()) - INTERVAL '3 days' + INTERVAL '12 hours', 'Moscow, Tverskaya, 1', 'Lenina, 1', 1, 'delivered', 1, NULL),
+    (1, date_trunc('day', now()) - INTERVAL '3 days' + INTERVAL '12 hours', 'Moscow, Tverskaya, 1', 'Lenina, 1', 2, 'delivered', 2, NULL);
+
+INSERT INTO orders_composition (order_id, dish_id, commodity_id, unit_price, quantity) VALUES
+    (1, 1, NULL, 35000, 2),
+    (1, 2, NULL, 35000, 1),
+    (2, 1, NULL, 35000, 1),
+    (2, NULL, 1, 6000, 2);
@@ -0,0 +1,115 @@
+package fixtures
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+	"github.com/LeKSuS-04/mephi-db/internal/views"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// Fixture is a small hand-written dataset. Empty fixture has no SQL and
+// leaves all tables empty.
+type Fixture struct {
+	Name string
+	SQL  string
+}
+
+// Empty is a fixture without any rows.
+const Empty = "empty"
+
+// Since is the earliest timestamp of orders and payments in fixtures. Other
+// fixtures use timestamps relative to now() within the last month.
+var Since = time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+// All returns empty fixture followed by embedded ones ordered by name.
+func All() ([]Fixture, error) {
+	names, err := fs.Glob(files, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+
+	fixtures := []Fixture{{Name: Empty}}
+	for _, name := range names {
+		sql, err := files.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("read fixture %q: %w", name, err)
+		}
+		fixtures = append(fixtures, Fixture{
+			Name: strings.TrimSuffix(name, ".sql"),
+			SQL:  string(sql),
+		})
+	}
+	return fixtures, nil
+}
+
+// Find returns fixtures with given names.
+func Find(names []string) ([]Fixture, error) {
+	all, err := All()
+	if err != nil {
+		return nil, err
+	}
+	found := make([]Fixture, 0, len(names))
+	for _, name := range names {
+		idx := slices.IndexFunc(all, func(f Fixture) bool { return f.Name == name })
+		if idx == -1 {
+			return nil, fmt.Errorf("unknown fixture %q", name)
+		}
+		found = append(found, all[idx])
+	}
+	return found, nil
+}
+
+// Load truncates every table, fills them with fixture rows, stores order
+// amounts the same way generator does and refreshes materialized views. It
+// is meant to be run inside a transaction which is rolled back afterwards,
+// so that existing data is left intact. If partitioning is enabled,
+// partitions between Since and now must exist.
+func Load(ctx context.Context, tx pgx.Tx, f Fixture) error {
+	rows, err := tx.Query(ctx, `
+		SELECT relname
+		FROM pg_class
+		WHERE relnamespace = 'public'::regnamespace
+			AND relkind IN ('r', 'p')
+			AND NOT relispartition
+			AND relname <> 'schema_migrations'
+		ORDER BY relname
+	`)
+	if err != nil {
+		return fmt.Errorf("list tables: %w", err)
+	}
+	tables, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("list tables: %w", err)
+	}
+
+	identifiers := make([]string, len(tables))
+	for i, table := range tables {
+		identifiers[i] = pgx.Identifier{table}.Sanitize()
+	}
+	truncate := "TRUNCATE " + strings.Join(identifiers, ", ") + " RESTART IDENTITY CASCADE"
+	if _, err := tx.Exec(ctx, truncate); err != nil {
+		return fmt.Errorf("truncate tables: %w", err)
+	}
+
+	if f.SQL != "" {
+		if _, err := tx.Exec(ctx, f.SQL); err != nil {
+			return fmt.Errorf("load fixture %q: %w", f.Name, err)
+		}
+	}
+	if _, err := queries.New(tx).FillOrderAmounts(ctx); err != nil {
+		return fmt.Errorf("fill order amounts: %w", err)
+	}
+	return views.Refresh(ctx, tx, false)
+}
@@ -0,0 +1,67 @@
+-- Rows with every nullable column left NULL next to fully filled ones, and
+-- entities without any related rows: user without orders, cards or
+-- addresses, supplier without dishes, dish never ordered, order without
+-- payment, courier, discount and status history, payment without order. Partition keys
+-- (timestamps of orders and payments) are never NULL, since such rows can
+-- not be routed to a partition. Pelmeni in the canceled order were bought
+-- before a price change, so its unit price differs from the current cost.
+
+INSERT INTO users (name, surname, email, phone, password_hash) VALUES
+    ('Ivan', 'Ivanov', 'ivanov@example.com', '+7 900 000-00-01', 'hash'),
+    (NULL, NULL, 'anonymous@example.com', NULL, NULL),
+    ('Petr', 'Petrov', 'petrov@example.com', '+7 900 000-00-03', 'hash');
+
+INSERT INTO user_addresses (user_id, address) VALUES
+    (1, 'Kashirskoe shosse, 31');
+
+INSERT INTO user_cards (user_id, number) VALUES
+    (1, '4000 0000 0000 0001'),
+    (3, '4000 0000 0000 0003');
+
+INSERT INTO payments (method, card_id, timestamp, status) VALUES
+    ('card', 1, date_trunc('day', now()) - INTERVAL '2 days' + INTERVAL '12 hours', 'successful'),
+    ('cash', NULL, date_trunc('day', now()) - INTERVAL '20 days' + INTERVAL '9 hours', 'successful'),
+    ('online', 2, date_trunc('day', now()) - INTERVAL '1 day' + INTERVAL '18 hours', 'failed');
+
+INSERT INTO couriers (name, phone, rating) VALUES
+    ('Courier', '+7 900 000-00-10', 4.50);
+
+INSERT INTO discounts (name, description, type, terms, active) VALUES
+    ('Ten percent', 'Ten percent off', 'percentage', '{"percentage": 10}', true),
+    ('Two for one', 'Extra dish for free', 'extra_for_free', '{"must_by": 1, "get_for_free": 1}', false);
+
+INSERT INTO suppliers (name, work_time_start, work_time_end, rating, address) VALUES
+    ('Restaurant', '09:00', '21:00', 4.80, 'Moscow, Tverskaya, 1'),
+    ('Shop', '00:00', '23:59', 3.20, 'Moscow, Arbat, 2'),
+    ('Empty supplier', '10:00', '18:00', 0.00, 'Moscow, Lenina, 3');
+
+INSERT INTO dishes (supplier_id, name, cost, image, ingredients, weight, calories, allergens, rating) VALUES
+    (1, 'Borsch', 35000, NULL, 'beet, cabbage, meat', 400, 250, 'none', 4.90),
+    (1, 'Water', 5000, NULL, NULL, 500, 0, 'none', 1.00),
+    (1, 'Pelmeni', 42000, '\x00', 'meat, flour', 300, 600, 'gluten', 4.10);
+
+INSERT INTO commodities (supplier_id, name, cost, image, ingredients, weight, rating) VALUES
+    (2, 'Bread', 6000, NULL, 'flour, water', 500, 4.00),
+    (2, 'Milk', 9000, NULL, 'milk', 1000, 3.50);
+
+INSERT INTO orders (user_id, timestamp, source_address, target_address, courier_id, status, payment_id, discount_id) VALUES
+    (1, date_trunc('day', now()) - INTERVAL '2 days' + INTERVAL '12 hours', 'Moscow, Tverskaya, 1', 'Kashirskoe shosse, 31', 1, 'delivered', 1, 1),
+    (1, date_trunc('day', now()) - INTERVAL '20 days' + INTERVAL '9 hours', NULL, NULL, NULL, 'canceled', 2, NULL),
+    (3, date_trunc('day', now()) - INTERVAL '1 day' + INTERVAL '18 hours', 'Moscow, Arbat, 2', NULL, NULL, 'created', NULL, NULL);
+
+INSERT INTO orders_composition (order_id, dish_id, commodity_id, unit_price) VALUES
+    (1, 1, NULL, 35000),
+    (1, NULL, 1, 6000),
+    (2, 3, NULL, 40000),
+    (3, NULL, 2, 9000);
+
+INSERT INTO discount_to_targets (dish_id, commodity_id, discount_id) VALUES
+    (1, NULL, 1),
+    (NULL, 2, 2);
+
+INSERT INTO categories (name) VALUES
+    ('Soups'),
+    ('Empty category');
+
+INSERT INTO categories_to_targets (dish_id, commodity_id, category_id) VALUES
+    (1, NULL, 1);
@@ -0,0 +1,133 @@
+-- Small but complete dataset with fixed timestamps in March 2024, matching
+-- default parameters of reports. Used for golden results, so changing it
+-- requires regenerating them with `go test ./internal/golden -update`.
+
+INSERT INTO users (name, surname, email, phone, password_hash) VALUES
+    ('Ivan', 'Ivanov', 'ivanov@example.com', '+7 900 000-00-01', 'hash'),
+    ('Irina', 'Ivanova', 'ivanova@example.com', '+7 900 000-00-02', 'hash'),
+    ('Petr', 'Petrov', 'petrov@example.com', '+7 900 000-00-03', 'hash'),
+    ('Olga', 'Sidorova', 'sidorova@example.com', '+7 900 000-00-04', 'hash');
+
+INSERT INTO user_addresses (user_id, address) VALUES
+    (1, 'Moscow, Kashirskoe shosse, 31'),
+    (2, 'Moscow, Leninsky prospekt, 10'),
+    (4, 'Moscow, Profsoyuznaya, 5');
+
+INSERT INTO user_cards (user_id, number) VALUES
+    (1, '4000 0000 0000 0001'),
+    (2, '4000 0000 0000 0002'),
+    (4, '4000 0000 0000 0004');
+
+INSERT INTO payments (method, card_id, timestamp, status) VALUES
+    ('card', 1, '2024-03-01 08:15', 'successful'),
+    ('online', 2, '2024-03-02 13:40', 'successful'),
+    ('cash', NULL, '2024-03-05 19:05', 'successful'),
+    ('card', 1, '2024-03-10 23:30', 'failed'),
+    ('card', 1, '2024-03-10 23:35', 'successful'),
+    ('online', 2, '2024-03-15 12:00', 'failed'),
+    ('cash', NULL, '2024-03-20 07:45', 'successful'),
+    ('card', 2, '2024-03-28 02:10', 'successful');
+
+INSERT INTO couriers (name, phone, rating) VALUES
+    ('Sergey', '+7 900 000-01-01', 4.90),
+    ('Dmitry', '+7 900 000-01-02', 4.20),
+    ('Anton', '+7 900 000-01-03', 3.70);
+
+INSERT INTO discounts (name, description, type, terms, active) VALUES
+    ('Ten percent', 'Ten percent off', 'percentage', '{"percentage": 10}', true),
+    ('Minus 300', 'Three hundred off', 'const', '{"value": 300}', true),
+    ('Two for one', 'Second one for free', 'extra_for_free', '{"must_by": 1, "get_for_free": 1}', true);
+
+INSERT INTO suppliers (name, work_time_start, work_time_end, rating, address) VALUES
+    ('Pelmennaya', '09:00', '23:00', 4.70, 'Moscow, Tverskaya, 12'),
+    ('Stolovaya', '08:00', '20:00', 4.10, 'Moscow, Arbat, 3'),
+    ('Produkty', '00:00', '23:59', 3.90, 'Moscow, Tverskaya, 20');
+
+INSERT INTO dishes (supplier_id, name, cost, image, ingredients, weight, calories, allergens, rating) VALUES
+    (1, 'Pelmeni', 45000, NULL, 'meat, flour, onion', 300, 650, 'gluten', 4.80),
+    (1, 'Vareniki', 38000, NULL, 'potato, flour', 300, 500, 'gluten', 4.60),
+    (1, 'Borsch', 32000, NULL, 'beet, cabbage, meat', 400, 250, 'none', 4.90),
+    (2, 'Borsch', 25000, NULL, 'beet, cabbage, meat', 400, 250, 'none', 4.00),
+    (2, 'Kompot', 8000, NULL, 'apple, sugar', 250, 90, 'none', 3.80),
+    (2, 'Olivier', 21000, NULL, 'potato, egg, meat', 200, 350, 'egg', 4.30);
+
+INSERT INTO commodities (supplier_id, name, cost, image, ingredients, weight, rating) VALUES
+    (3, 'Bread', 6000, NULL, 'flour, water', 500, 4.00),
+    (3, 'Milk', 9000, NULL, 'milk', 1000, 3.50),
+    (2, 'Bread', 7000, NULL, 'flour, water, salt', 450, 4.20);
+
+INSERT INTO orders (user_id, timestamp, source_address, target_address, courier_id, status, payment_id, discount_id) VALUES
+    (1, '2024-03-01 08:15', 'Moscow, Tverskaya, 12', 'Moscow, Kashirskoe shosse, 31', 1, 'delivered', 1, NULL),
+    (2, '2024-03-02 13:40', 'Moscow, Arbat, 3', 'Moscow, Leninsky prospekt, 10', 2, 'delivered', 2, 1),
+    (3, '2024-03-05 19:05', 'Moscow, Tverskaya, 20', 'Moscow, Ostozhenka, 7', 1, 'delivered', 3, NULL),
+    (1, '2024-03-10 23:35', 'Moscow, Tverskaya, 12', 'Moscow, Kashirskoe shosse, 31', 3, 'delivered', 5, 3),
+    (2, '2024-03-15 12:00', 'Moscow, Arbat, 3', 'Moscow, Tverskaya, 1', NULL, 'canceled', 6, NULL),
+    (1, '2024-03-20 07:45', 'Moscow, Tverskaya, 20', 'Moscow, Tverskaya, 1', 2, 'delivered', 7, 2),
+    (2, '2024-03-28 02:10', 'Moscow, Tverskaya, 12', 'Moscow, Leninsky prospekt, 10', 1, 'picked_up', 8, NULL);
+
+INSERT INTO order_status_history (order_id, status, timestamp) VALUES
+    (1, 'created', '2024-03-01 08:15'),
+    (1, 'paid', '2024-03-01 08:17'),
+    (1, 'accepted', '2024-03-01 08:25'),
+    (1, 'picked_up', '2024-03-01 08:50'),
+    (1, 'delivered', '2024-03-01 09:20'),
+    (2, 'created', '2024-03-02 13:40'),
+    (2, 'paid', '2024-03-02 13:42'),
+    (2, 'accepted', '2024-03-02 13:50'),
+    (2, 'picked_up', '2024-03-02 14:05'),
+    (2, 'delivered', '2024-03-02 14:35'),
+    (3, 'created', '2024-03-05 19:05'),
+    (3, 'paid', '2024-03-05 19:06'),
+    (3, 'accepted', '2024-03-05 19:15'),
+    (3, 'picked_up', '2024-03-05 19:40'),
+    (3, 'delivered', '2024-03-05 20:25'),
+    (4, 'created', '2024-03-10 23:35'),
+    (4, 'paid', '2024-03-10 23:38'),
+    (4, 'accepted', '2024-03-10 23:45'),
+    (4, 'picked_up', '2024-03-11 00:10'),
+    (4, 'delivered', '2024-03-11 00:40'),
+    (5, 'created', '2024-03-15 12:00'),
+    (5, 'canceled', '2024-03-15 12:20'),
+    (6, 'created', '2024-03-20 07:45'),
+    (6, 'paid', '2024-03-20 07:47'),
+    (6, 'accepted', '2024-03-20 07:55'),
+    (6, 'picked_up', '2024-03-20 08:20'),
+    (6, 'delivered', '2024-03-20 08:45'),
+    (7, 'created', '2024-03-28 02:10'),
+    (7, 'paid', '2024-03-28 02:12'),
+    (7, 'accepted', '2024-03-28 02:20'),
+    (7, 'picked_up', '2024-03-28 02:45');
+
+INSERT INTO orders_composition (order_id, dish_id, commodity_id, unit_price, quantity) VALUES
+    (1, 1, NULL, 45000, 1),
+    (1, 3, NULL, 32000, 1),
+    (2, 4, NULL, 25000, 1),
+    (2, 5, NULL, 8000, 1),
+    (2, NULL, 3, 7000, 1),
+    (3, NULL, 1, 6000, 1),
+    (3, NULL, 2, 9000, 1),
+    (4, 1, NULL, 45000, 2),
+    (5, 6, NULL, 21000, 1),
+    (6, NULL, 1, 6000, 1),
+    (7, 2, NULL, 38000, 1),
+    (7, 3, NULL, 32000, 1);
+
+INSERT INTO discount_to_targets (dish_id, commodity_id, discount_id) VALUES
+    (4, NULL, 1),
+    (5, NULL, 1),
+    (NULL, 1, 2),
+    (1, NULL, 3);
+
+INSERT INTO categories (name) VALUES
+    ('Soups'),
+    ('Dumplings'),
+    ('Groceries');
+
+INSERT INTO categories_to_targets (dish_id, commodity_id, category_id) VALUES
+    (3, NULL, 1),
+    (4, NULL, 1),
+    (1, NULL, 2),
+    (2, NULL, 2),
+    (NULL, 1, 3),
+    (NULL, 2, 3),
+    (NULL, 3, 3);
@@ -2,10 +2,11 @@ package gen
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"log"
 	"math/rand/v2"
+	"slices"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 
@@ -28,242 +29,272 @@ const (
 	OrderCount       = 300 * AmountAmplifier
 	MinItemsPerOrder = 1
 	MaxItemsPerOrder = 10
+	MaxItemQuantity  = 5
 
 	SupplierCount       = max(5, AmountAmplifier/10)
 	MinItemsPerSupplier = 3
 	MaxItemsPerSupplier = 10
 
 	DiscountCount = max(5, AmountAmplifier/2)
+
+	HistoryDepth = 365 * 24 * time.Hour
 )
 
-func Generate(q *queries.Queries) error {
+// Config sets amounts of generated rows. Min and max bounds are inclusive.
+// Amounts of dishes and commodities per supplier and items per order are
+// also capped by amounts of available ones.
+type Config struct {
+	UserCount           int
+	CardsPerUserMin     int
+	CardsPerUserMax     int
+	AddressesPerUserMin int
+	AddressesPerUserMax int
+
+	CourierCount int
+
+	OrderCount       int
+	MinItemsPerOrder int
+	MaxItemsPerOrder int
+	MaxItemQuantity  int
+
+	SupplierCount       int
+	MinItemsPerSupplier int
+	MaxItemsPerSupplier int
+
+	DiscountCount int
+
+	// HistoryDepth is how long ago the earliest orders and payments may be
+	// made.
+	HistoryDepth time.Duration
+}
+
+// DefaultConfig is the amount of data generate command fills database with.
+var DefaultConfig = Config{
+	UserCount:           UserCount,
+	CardsPerUserMin:     CardsPerUserMin,
+	CardsPerUserMax:     CardsPerUserMax,
+	AddressesPerUserMin: AddressesPerUserMin,
+	AddressesPerUserMax: AddressesPerUserMax,
+	CourierCount:        CourierCount,
+	OrderCount:          OrderCount,
+	MinItemsPerOrder:    MinItemsPerOrder,
+	MaxItemsPerOrder:    MaxItemsPerOrder,
+	MaxItemQuantity:     MaxItemQuantity,
+	SupplierCount:       SupplierCount,
+	MinItemsPerSupplier: MinItemsPerSupplier,
+	MaxItemsPerSupplier: MaxItemsPerSupplier,
+	DiscountCount:       DiscountCount,
+	HistoryDepth:        HistoryDepth,
+}
+
+// Validate checks that bounds are ordered and amounts are non-negative.
+// Orders are paid with cards of users and delivered by couriers, so they
+// require at least one of each.
+func (c Config) Validate() error {
+	counts := []struct {
+		name string
+		n    int
+	}{
+		{"users", c.UserCount},
+		{"couriers", c.CourierCount},
+		{"orders", c.OrderCount},
+		{"suppliers", c.SupplierCount},
+		{"discounts", c.DiscountCount},
+		{"min cards per user", c.CardsPerUserMin},
+		{"min addresses per user", c.AddressesPerUserMin},
+		{"min items per order", c.MinItemsPerOrder},
+		{"min items per supplier", c.MinItemsPerSupplier},
+	}
+	for _, count := range counts {
+		if count.n < 0 {
+			return fmt.Errorf("%s must not be negative, got %d", count.name, count.n)
+		}
+	}
+
+	bounds := []struct {
+		name     string
+		min, max int
+	}{
+		{"cards per user", c.CardsPerUserMin, c.CardsPerUserMax},
+		{"addresses per user", c.AddressesPerUserMin, c.AddressesPerUserMax},
+		{"items per order", c.MinItemsPerOrder, c.MaxItemsPerOrder},
+		{"items per supplier", c.MinItemsPerSupplier, c.MaxItemsPerSupplier},
+	}
+	for _, b := range bounds {
+		if b.min > b.max {
+			return fmt.Errorf("min %s %d exceeds max %d", b.name, b.min, b.max)
+		}
+	}
+
+	if c.MaxItemQuantity < 1 {
+		return fmt.Errorf("max item quantity must be positive, got %d", c.MaxItemQuantity)
+	}
+	if c.HistoryDepth <= 0 {
+		return fmt.Errorf("history depth must be positive, got %s", c.HistoryDepth)
+	}
+	if c.OrderCount > 0 && (c.UserCount == 0 || c.CardsPerUserMin == 0 || c.CourierCount == 0) {
+		return fmt.Errorf("orders require users, at least one card per user and couriers")
+	}
+	return nil
+}
+
+// Generate fills database with dummy data in amounts set by cfg and returns
+// spans of generation tasks, which are available even if generation failed.
+func Generate(q *queries.Queries, cfg Config) ([]launcher.Span, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
 	launch := launcher.New()
 
 	usersFut := future.New[[]int32]()
 	userCardsFut := future.New[[]int32]()
 	couriersFut := future.New[[]int32]()
-	paymentsFut := future.New[[]int32]()
+	paymentsFut := future.New[[]queries.SelectPaymentCardsRow]()
 	ordersFut := future.New[[]int32]()
 	suppliersFut := future.New[[]int32]()
 	dishesFut := future.New[[]int32]()
 	commoditiesFut := future.New[[]int32]()
 	categoriesFut := future.New[[]int32]()
 	discountsFut := future.New[[]int32]()
+	compositionsFut := future.New[struct{}]()
+	discountTargetsFut := future.New[struct{}]()
 
-	launch.Go(func() error {
-		userIDs, err := createUsers(q)
-		if err != nil {
-			usersFut.Cancel()
-			return err
-		}
-		usersFut.Set(userIDs)
-		return err
-	})
+	launch.GoNamed("users", produce(usersFut, func() ([]int32, error) {
+		return createUsers(q, cfg)
+	}))
 
-	launch.Go(func() (err error) {
-		defer func() {
-			if err != nil {
-				userCardsFut.Cancel()
-			}
-		}()
+	launch.GoNamed("cards", produce(userCardsFut, func() ([]int32, error) {
 		userIDs, err := usersFut.Get()
 		if err != nil {
-			return errors.New("users not created")
+			return nil, err
 		}
-		userCardIDs, err := createCards(q, userIDs)
-		if err != nil {
-			return err
-		}
-		userCardsFut.Set(userCardIDs)
-		return nil
-	})
+		return createCards(q, cfg, userIDs)
+	}))
 
-	launch.Go(func() error {
+	launch.GoNamed("addresses", func() error {
 		userIDs, err := usersFut.Get()
 		if err != nil {
-			return errors.New("users not created")
-		}
-		return createAddresses(q, userIDs)
-	})
-
-	launch.Go(func() error {
-		courierIDs, err := createCouriers(q)
-		if err != nil {
-			couriersFut.Cancel()
 			return err
 		}
-		couriersFut.Set(courierIDs)
-		return nil
+		return createAddresses(q, cfg, userIDs)
 	})
 
-	launch.Go(func() (err error) {
-		defer func() {
-			if err != nil {
-				paymentsFut.Cancel()
-			}
-		}()
+	launch.GoNamed("couriers", produce(couriersFut, func() ([]int32, error) {
+		return createCouriers(q, cfg)
+	}))
+
+	launch.GoNamed("payments", produce(paymentsFut, func() ([]queries.SelectPaymentCardsRow, error) {
 		userCardIDs, err := userCardsFut.Get()
 		if err != nil {
-			return errors.New("cards not created")
+			return nil, err
 		}
-		paymentIDs, err := createPayments(q, userCardIDs)
-		if err != nil {
-			paymentsFut.Cancel()
-			return err
-		}
-		paymentsFut.Set(paymentIDs)
-		return nil
-	})
+		return createPayments(q, cfg, userCardIDs)
+	}))
 
-	launch.Go(func() (err error) {
-		defer func() {
-			if err != nil {
-				ordersFut.Cancel()
-			}
-		}()
-
-		userIDs, err := usersFut.Get()
-		if err != nil {
-			return errors.New("users not created")
-		}
-		courierIDs, err := couriersFut.Get()
-		if err != nil {
-			return errors.New("couriers not created")
-		}
-		paymentIDs, err := paymentsFut.Get()
+	launch.GoNamed("orders", produce(ordersFut, func() ([]int32, error) {
+		deps, err := future.Zip3(usersFut, couriersFut, paymentsFut).Get()
 		if err != nil {
-			return errors.New("payments not created")
+			return nil, err
 		}
-		orderIDs, err := createOrders(q, userIDs, courierIDs, paymentIDs)
-		if err != nil {
-			return err
-		}
-		ordersFut.Set(orderIDs)
-		return err
-	})
+		return createOrders(q, cfg, deps.First, deps.Second, deps.Third)
+	}))
 
-	launch.Go(func() error {
-		supplierIDs, err := createSuppliers(q)
-		if err != nil {
-			suppliersFut.Cancel()
-			return err
-		}
-		suppliersFut.Set(supplierIDs)
-		return nil
-	})
+	launch.GoNamed("suppliers", produce(suppliersFut, func() ([]int32, error) {
+		return createSuppliers(q, cfg)
+	}))
 
-	launch.Go(func() (err error) {
-		defer func() {
-			if err != nil {
-				dishesFut.Cancel()
-			}
-		}()
+	launch.GoNamed("dishes", produce(dishesFut, func() ([]int32, error) {
 		supplierIDs, err := suppliersFut.Get()
 		if err != nil {
-			return errors.New("suppliers not created")
+			return nil, err
 		}
-		dishIDs, err := createDishes(q, supplierIDs)
-		if err != nil {
-			return err
-		}
-		dishesFut.Set(dishIDs)
-		return nil
-	})
+		return createDishes(q, cfg, supplierIDs)
+	}))
 
-	launch.Go(func() (err error) {
-		defer func() {
-			if err != nil {
-				commoditiesFut.Cancel()
-			}
-		}()
+	launch.GoNamed("commodities", produce(commoditiesFut, func() ([]int32, error) {
 		supplierIDs, err := suppliersFut.Get()
 		if err != nil {
-			return errors.New("suppliers not created")
+			return nil, err
 		}
-		commodityIDs, err := createCommodities(q, supplierIDs)
-		if err != nil {
+		return createCommodities(q, cfg, supplierIDs)
+	}))
+
+	launch.GoNamed("order histories", func() error {
+		if _, err := ordersFut.Get(); err != nil {
 			return err
 		}
-		commoditiesFut.Set(commodityIDs)
-		return nil
+		return createOrderHistories(q)
 	})
 
-	launch.Go(func() error {
-		orderIDs, err := ordersFut.Get()
-		if err != nil {
-			return errors.New("orders not created")
-		}
-		dishIDs, err := dishesFut.Get()
-		if err != nil {
-			return errors.New("dishes not created")
-		}
-		commodityIDs, err := commoditiesFut.Get()
+	launch.GoNamed("order compositions", produce(compositionsFut, func() (struct{}, error) {
+		deps, err := future.Zip3(ordersFut, dishesFut, commoditiesFut).Get()
 		if err != nil {
-			return errors.New("commodities not created")
+			return struct{}{}, err
 		}
-		return createOrderCompositions(q, orderIDs, dishIDs, commodityIDs)
-	})
+		return struct{}{}, createOrderCompositions(q, cfg, deps.First, deps.Second, deps.Third)
+	}))
+
+	launch.GoNamed("categories", produce(categoriesFut, func() ([]int32, error) {
+		return createCategories(q)
+	}))
 
-	launch.Go(func() error {
-		categoryIDs, err := createCategories(q)
+	launch.GoNamed("categories to targets", func() error {
+		deps, err := future.Zip3(categoriesFut, dishesFut, commoditiesFut).Get()
 		if err != nil {
-			categoriesFut.Cancel()
 			return err
 		}
-		categoriesFut.Set(categoryIDs)
-		return nil
+		return createCategoriesToTargets(q, deps.First, deps.Second, deps.Third)
 	})
 
-	launch.Go(func() error {
-		categoryIDs, err := categoriesFut.Get()
-		if err != nil {
-			return errors.New("categories not created")
-		}
-		dishIDs, err := dishesFut.Get()
-		if err != nil {
-			return errors.New("dishes not created")
-		}
-		commodityIDs, err := commoditiesFut.Get()
+	launch.GoNamed("discounts", produce(discountsFut, func() ([]int32, error) {
+		return createDiscounts(q, cfg)
+	}))
+
+	launch.GoNamed("discounts to targets", produce(discountTargetsFut, func() (struct{}, error) {
+		deps, err := future.Zip3(discountsFut, dishesFut, commoditiesFut).Get()
 		if err != nil {
-			return errors.New("commodities not created")
+			return struct{}{}, err
 		}
-		return createCategoriesToTargets(q, categoryIDs, dishIDs, commodityIDs)
-	})
+		return struct{}{}, createDiscountsToTargets(q, deps.First, deps.Second, deps.Third)
+	}))
 
-	launch.Go(func() error {
-		discountIDs, err := createDiscounts(q)
-		if err != nil {
-			discountsFut.Cancel()
+	// Amounts depend on both compositions and discount targets, so they are
+	// stored once both are created.
+	launch.GoNamed("order amounts", func() error {
+		if _, err := future.Zip2(compositionsFut, discountTargetsFut).Get(); err != nil {
 			return err
 		}
-		discountsFut.Set(discountIDs)
-		return nil
+		return fillOrderAmounts(q)
 	})
 
-	launch.Go(func() error {
-		discountIDs, err := discountsFut.Get()
-		if err != nil {
-			return errors.New("discounts not created")
-		}
-		dishIDs, err := dishesFut.Get()
-		if err != nil {
-			return errors.New("dishes not created")
-		}
-		commodityIDs, err := commoditiesFut.Get()
+	err := launch.Wait()
+	return launch.Spans(), err
+}
+
+// produce returns task resolving fut with result of f. Future is canceled
+// with error of f, so that tasks waiting for it see the cause.
+func produce[T any](fut *future.Future[T], f func() (T, error)) func() error {
+	return func() error {
+		// Does nothing once future is resolved, and keeps tasks waiting for
+		// it from blocking forever if f panics.
+		defer fut.Cancel()
+		value, err := f()
 		if err != nil {
-			return errors.New("commodities not created")
+			fut.CancelWithError(err)
+			return err
 		}
-		return createDiscountsToTargets(q, discountIDs, dishIDs, commodityIDs)
-	})
-
-	return launch.Wait()
+		fut.Set(value)
+		return nil
+	}
 }
 
-func createUsers(q *queries.Queries) ([]int32, error) {
-	log.Printf("Generating %d users", UserCount)
-	users := make([]queries.CreateUsersParams, 0, UserCount)
-	for range UserCount {
-		users = append(users, randomUser())
+func createUsers(q *queries.Queries, cfg Config) ([]int32, error) {
+	log.Printf("Generating %d users", cfg.UserCount)
+	users := make([]queries.CreateUsersParams, 0, cfg.UserCount)
+	usedEmails := make(map[string]struct{}, cfg.UserCount)
+	for range cfg.UserCount {
+		users = append(users, randomUser(usedEmails))
 	}
 	log.Printf("Creating %d users", len(users))
 	if _, err := q.CreateUsers(context.Background(), users); err != nil {
@@ -279,12 +310,12 @@ func createUsers(q *queries.Queries) ([]int32, error) {
 	return userIDs, nil
 }
 
-func createCards(q *queries.Queries, userIDs []int32) ([]int32, error) {
+func createCards(q *queries.Queries, cfg Config, userIDs []int32) ([]int32, error) {
 	log.Print("Generating cards")
-	cards := make([]queries.CreateUserCardsParams, 0, len(userIDs)*CardsPerUserMax)
+	cards := make([]queries.CreateUserCardsParams, 0, len(userIDs)*cfg.CardsPerUserMax)
 	totalCards := 0
 	for _, userID := range userIDs {
-		userCardCount := rand.IntN(CardsPerUserMax-CardsPerUserMin) + CardsPerUserMin
+		userCardCount := randomCount(cfg.CardsPerUserMin, cfg.CardsPerUserMax)
 		totalCards += userCardCount
 		for i := 0; i < userCardCount; i++ {
 			cards = append(cards, randomCard(userID))
@@ -305,12 +336,12 @@ func createCards(q *queries.Queries, userIDs []int32) ([]int32, error) {
 	return cardIDs, nil
 }
 
-func createAddresses(q *queries.Queries, userIDs []int32) error {
+func createAddresses(q *queries.Queries, cfg Config, userIDs []int32) error {
 	log.Print("Generating addresses")
-	cards := make([]queries.CreateUserAddressesParams, 0, len(userIDs)*AddressesPerUserMax)
+	cards := make([]queries.CreateUserAddressesParams, 0, len(userIDs)*cfg.AddressesPerUserMax)
 	totalAddresses := 0
 	for _, userID := range userIDs {
-		userAddressCount := rand.IntN(AddressesPerUserMax-AddressesPerUserMin) + AddressesPerUserMin
+		userAddressCount := randomCount(cfg.AddressesPerUserMin, cfg.AddressesPerUserMax)
 		totalAddresses += userAddressCount
 		for i := 0; i < userAddressCount; i++ {
 			cards = append(cards, randomAddress(userID))
@@ -325,10 +356,10 @@ func createAddresses(q *queries.Queries, userIDs []int32) error {
 	return nil
 }
 
-func createCouriers(q *queries.Queries) ([]int32, error) {
-	log.Printf("Generating %d couriers", CourierCount)
-	couriers := make([]queries.CreateCourieresParams, 0, CourierCount)
-	for range CourierCount {
+func createCouriers(q *queries.Queries, cfg Config) ([]int32, error) {
+	log.Printf("Generating %d couriers", cfg.CourierCount)
+	couriers := make([]queries.CreateCourieresParams, 0, cfg.CourierCount)
+	for range cfg.CourierCount {
 		couriers = append(couriers, randomCourier())
 	}
 
@@ -346,33 +377,56 @@ func createCouriers(q *queries.Queries) ([]int32, error) {
 	return courierIDs, nil
 }
 
-func createPayments(q *queries.Queries, cardIDs []int32) ([]int32, error) {
-	log.Printf("Generating %d payments", OrderCount)
-	payments := make([]queries.CreatePaymentsParams, 0, OrderCount)
-	for range OrderCount {
-		payments = append(payments, randomPayment(cardIDs))
+func createPayments(q *queries.Queries, cfg Config, cardIDs []int32) ([]queries.SelectPaymentCardsRow, error) {
+	log.Printf("Generating %d payments", cfg.OrderCount)
+	payments := make([]queries.CreatePaymentsParams, 0, cfg.OrderCount)
+	for range cfg.OrderCount {
+		payments = append(payments, randomPayment(cardIDs, cfg.HistoryDepth))
 	}
+	// Sorted rows let COPY into partitioned table route to one partition at
+	// a time instead of jumping between them on every row.
+	slices.SortFunc(payments, func(a, b queries.CreatePaymentsParams) int {
+		return a.Timestamp.Time.Compare(b.Timestamp.Time)
+	})
 
 	log.Printf("Creating %d payments", len(payments))
 	if _, err := q.CreatePayments(context.Background(), payments); err != nil {
 		return nil, fmt.Errorf("create payments: %w", err)
 	}
 
-	log.Print("Selecting payment ids")
-	paymentIDs, err := q.SelectPaymentIDs(context.Background())
+	log.Print("Selecting payment cards")
+	paymentCards, err := q.SelectPaymentCards(context.Background())
 	if err != nil {
-		return nil, fmt.Errorf("select payment ids: %w", err)
+		return nil, fmt.Errorf("select payment cards: %w", err)
 	}
 
-	return paymentIDs, nil
+	return paymentCards, nil
 }
 
-func createOrders(q *queries.Queries, userIDs, courierIDs, paymentIDs []int32) ([]int32, error) {
-	log.Printf("Generating %d orders", OrderCount)
-	orders := make([]queries.CreateOrdersParams, 0, OrderCount)
-	for i := range OrderCount {
-		orders = append(orders, randomOrder(paymentIDs[i], userIDs, courierIDs))
+func createOrders(q *queries.Queries, cfg Config, userIDs, courierIDs []int32, payments []queries.SelectPaymentCardsRow) ([]int32, error) {
+	log.Print("Selecting card owners")
+	cards, err := q.SelectUserCardOwners(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("select card owners: %w", err)
+	}
+	cardOwners := make(map[int32]int32, len(cards))
+	for _, c := range cards {
+		cardOwners[c.ID] = c.UserID
+	}
+
+	log.Printf("Generating %d orders", len(payments))
+	orders := make([]queries.CreateOrdersParams, 0, len(payments))
+	for _, p := range payments {
+		// Order paid with a card is made by the card owner.
+		userID := choose(userIDs)
+		if p.CardID.Valid {
+			userID = cardOwners[p.CardID.Int32]
+		}
+		orders = append(orders, randomOrder(userID, p.ID, courierIDs, cfg.HistoryDepth))
 	}
+	slices.SortFunc(orders, func(a, b queries.CreateOrdersParams) int {
+		return a.Timestamp.Time.Compare(b.Timestamp.Time)
+	})
 
 	log.Printf("Creating %d orders", len(orders))
 	if _, err := q.CreateOrders(context.Background(), orders); err != nil {
@@ -388,10 +442,59 @@ func createOrders(q *queries.Queries, userIDs, courierIDs, paymentIDs []int32) (
 	return orderIDs, nil
 }
 
-func createSuppliers(q *queries.Queries) ([]int32, error) {
-	log.Printf("Generating %d suppliers", SupplierCount)
-	suppliers := make([]queries.CreateSuppliersParams, 0, SupplierCount)
-	for range SupplierCount {
+func createOrderHistories(q *queries.Queries) error {
+	log.Print("Selecting order timestamps")
+	orders, err := q.SelectOrderTimestamps(context.Background())
+	if err != nil {
+		return fmt.Errorf("select order timestamps: %w", err)
+	}
+
+	log.Print("Selecting payment statuses")
+	payments, err := q.SelectPaymentStatuses(context.Background())
+	if err != nil {
+		return fmt.Errorf("select payment statuses: %w", err)
+	}
+	failedPayments := make(map[int32]struct{})
+	for _, p := range payments {
+		if p.Status == "failed" {
+			failedPayments[p.ID] = struct{}{}
+		}
+	}
+
+	log.Print("Generating order status histories")
+	now := time.Now()
+	history := make([]queries.CreateOrderStatusHistoryParams, 0, len(orders)*5)
+	for _, order := range orders {
+		_, paymentFailed := failedPayments[order.PaymentID.Int32]
+		paymentFailed = paymentFailed && order.PaymentID.Valid
+		for _, event := range randomHistory(order.Timestamp.Time, now, paymentFailed) {
+			history = append(history, queries.CreateOrderStatusHistoryParams{
+				OrderID: order.ID,
+				Status:  string(event.Status),
+				Timestamp: pgtype.Timestamp{
+					Time:  event.Timestamp,
+					Valid: true,
+				},
+			})
+		}
+	}
+
+	log.Printf("Creating %d order status history entries", len(history))
+	if _, err := q.CreateOrderStatusHistory(context.Background(), history); err != nil {
+		return fmt.Errorf("create order status history: %w", err)
+	}
+
+	log.Print("Syncing order statuses with their histories")
+	if _, err := q.SyncOrderStatuses(context.Background()); err != nil {
+		return fmt.Errorf("sync order statuses: %w", err)
+	}
+	return nil
+}
+
+func createSuppliers(q *queries.Queries, cfg Config) ([]int32, error) {
+	log.Printf("Generating %d suppliers", cfg.SupplierCount)
+	suppliers := make([]queries.CreateSuppliersParams, 0, cfg.SupplierCount)
+	for range cfg.SupplierCount {
 		suppliers = append(suppliers, randomSupplier())
 	}
 
@@ -408,13 +511,13 @@ func createSuppliers(q *queries.Queries) ([]int32, error) {
 	return supplierIDs, nil
 }
 
-func createDishes(q *queries.Queries, supplierIDs []int32) ([]int32, error) {
+func createDishes(q *queries.Queries, cfg Config, supplierIDs []int32) ([]int32, error) {
 	log.Print("Generating dishes")
 	dishes := make([]queries.CreateDishesParams, 0)
 	for _, supplierID := range supplierIDs {
 		h := hash(supplierID) % 3
 		if h == 0 || h == 1 {
-			dishCount := rand.IntN(MaxItemsPerSupplier-MinItemsPerSupplier) + MinItemsPerSupplier
+			dishCount := min(randomCount(cfg.MinItemsPerSupplier, cfg.MaxItemsPerSupplier), len(predefinedData.Dishes))
 			taken := make(map[int]struct{})
 			for i := 0; i < dishCount; i++ {
 				dishes = append(dishes, randomDish(supplierID, taken))
@@ -436,13 +539,13 @@ func createDishes(q *queries.Queries, supplierIDs []int32) ([]int32, error) {
 	return dishIDs, nil
 }
 
-func createCommodities(q *queries.Queries, supplierIDs []int32) ([]int32, error) {
+func createCommodities(q *queries.Queries, cfg Config, supplierIDs []int32) ([]int32, error) {
 	log.Print("Generating commodities")
 	commodities := make([]queries.CreateCommoditiesParams, 0)
 	for _, supplierID := range supplierIDs {
 		h := hash(supplierID) % 3
 		if h == 0 || h == 2 {
-			commodityCount := rand.IntN(MaxItemsPerSupplier-MinItemsPerSupplier) + MinItemsPerSupplier
+			commodityCount := min(randomCount(cfg.MinItemsPerSupplier, cfg.MaxItemsPerSupplier), len(predefinedData.Commodities))
 			taken := make(map[int]struct{})
 			for i := 0; i < commodityCount; i++ {
 				commodities = append(commodities, randomCommodity(supplierID, taken))
@@ -464,31 +567,57 @@ func createCommodities(q *queries.Queries, supplierIDs []int32) ([]int32, error)
 	return commodityIDs, nil
 }
 
-func createOrderCompositions(q *queries.Queries, orderIDs, dishIDs, commodityIDs []int32) error {
+func createOrderCompositions(q *queries.Queries, cfg Config, orderIDs, dishIDs, commodityIDs []int32) error {
+	log.Print("Selecting dish and commodity costs")
+	dishCosts, err := q.SelectDishCosts(context.Background())
+	if err != nil {
+		return fmt.Errorf("select dish costs: %w", err)
+	}
+	commodityCosts, err := q.SelectCommodityCosts(context.Background())
+	if err != nil {
+		return fmt.Errorf("select commodity costs: %w", err)
+	}
+	dishPrices := make(map[int32]int64, len(dishCosts))
+	for _, d := range dishCosts {
+		dishPrices[d.ID] = d.Cost
+	}
+	commodityPrices := make(map[int32]int64, len(commodityCosts))
+	for _, c := range commodityCosts {
+		commodityPrices[c.ID] = c.Cost
+	}
+
 	log.Print("Generating order compositions")
-	orderCompositions := make([]queries.AssignOrdersCommoditiesAndDishesParams, 0, len(orderIDs)*MaxItemsPerOrder)
+	orderCompositions := make([]queries.AssignOrdersCommoditiesAndDishesParams, 0, len(orderIDs)*cfg.MaxItemsPerOrder)
 	for _, orderID := range orderIDs {
-		itemCount := rand.IntN(MaxItemsPerOrder-MinItemsPerOrder) + MinItemsPerOrder
-		dishCount := rand.IntN(itemCount + 1)
-		commodityCount := itemCount - dishCount
+		itemCount := randomCount(cfg.MinItemsPerOrder, cfg.MaxItemsPerOrder)
+		dishCount := min(rand.IntN(itemCount+1), len(dishIDs))
+		commodityCount := min(itemCount-dishCount, len(commodityIDs))
 
+		chosenDishes := make(map[int]struct{}, dishCount)
 		for i := 0; i < dishCount; i++ {
+			dishID := chooseUniq(dishIDs, chosenDishes)
 			orderCompositions = append(orderCompositions, queries.AssignOrdersCommoditiesAndDishesParams{
 				OrderID: orderID,
 				DishID: pgtype.Int4{
-					Int32: dishIDs[rand.IntN(len(dishIDs))],
+					Int32: dishID,
 					Valid: true,
 				},
+				UnitPrice: dishPrices[dishID],
+				Quantity:  randomQuantity(cfg.MaxItemQuantity),
 			})
 		}
 
+		chosenCommodities := make(map[int]struct{}, commodityCount)
 		for i := 0; i < commodityCount; i++ {
+			commodityID := chooseUniq(commodityIDs, chosenCommodities)
 			orderCompositions = append(orderCompositions, queries.AssignOrdersCommoditiesAndDishesParams{
 				OrderID: orderID,
 				CommodityID: pgtype.Int4{
-					Int32: commodityIDs[rand.IntN(len(commodityIDs))],
+					Int32: commodityID,
 					Valid: true,
 				},
+				UnitPrice: commodityPrices[commodityID],
+				Quantity:  randomQuantity(cfg.MaxItemQuantity),
 			})
 		}
 	}
@@ -501,6 +630,16 @@ func createOrderCompositions(q *queries.Queries, orderIDs, dishIDs, commodityIDs
 	return nil
 }
 
+func fillOrderAmounts(q *queries.Queries) error {
+	log.Print("Filling order amounts")
+	n, err := q.FillOrderAmounts(context.Background())
+	if err != nil {
+		return fmt.Errorf("fill order amounts: %w", err)
+	}
+	log.Printf("Filled amounts of %d orders", n)
+	return nil
+}
+
 func createCategories(q *queries.Queries) ([]int32, error) {
 	log.Printf("Creating %d categories", len(categories))
 	if _, err := q.CreateCategories(context.Background(), categories); err != nil {
@@ -556,10 +695,10 @@ func createCategoriesToTargets(q *queries.Queries, categoryIDs, dishIDs, commodi
 	return nil
 }
 
-func createDiscounts(q *queries.Queries) ([]int32, error) {
+func createDiscounts(q *queries.Queries, cfg Config) ([]int32, error) {
 	log.Print("Creating discounts")
-	discounts := make([]queries.CreateDiscountsParams, 0, DiscountCount)
-	for i := 0; i < DiscountCount; i++ {
+	discounts := make([]queries.CreateDiscountsParams, 0, cfg.DiscountCount)
+	for i := 0; i < cfg.DiscountCount; i++ {
 		discounts = append(discounts, randomDiscount())
 	}
 
@@ -585,7 +724,7 @@ func createDiscountsToTargets(q *queries.Queries, discountIDs, dishIDs, commodit
 		var discountDishIDs, discountCommodityIDs []int32
 
 		if rand.IntN(3) != 0 {
-			discountDishIDs = make([]int32, rand.IntN(10)+1)
+			discountDishIDs = make([]int32, min(randomCount(1, 10), len(dishIDs)))
 			alreadyChosen := make(map[int]struct{}, len(discountDishIDs))
 			for i := range discountDishIDs {
 				discountDishIDs[i] = chooseUniq(dishIDs, alreadyChosen)
@@ -603,7 +742,7 @@ func createDiscountsToTargets(q *queries.Queries, discountIDs, dishIDs, commodit
 		}
 
 		if rand.IntN(3) != 0 || len(discountDishIDs) == 0 {
-			discountCommodityIDs = make([]int32, rand.IntN(10)+1)
+			discountCommodityIDs = make([]int32, min(randomCount(1, 10), len(commodityIDs)))
 			alreadyChosen := make(map[int]struct{}, len(discountCommodityIDs))
 			for i := range discountCommodityIDs {
 				discountCommodityIDs[i] = chooseUniq(commodityIDs, alreadyChosen)
@@ -0,0 +1,213 @@
+package gen
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/fakedb"
+	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+var smallConfig = Config{
+	UserCount:           20,
+	CardsPerUserMin:     1,
+	CardsPerUserMax:     3,
+	AddressesPerUserMin: 0,
+	AddressesPerUserMax: 2,
+	CourierCount:        3,
+	OrderCount:          50,
+	MinItemsPerOrder:    1,
+	MaxItemsPerOrder:    4,
+	MaxItemQuantity:     3,
+	SupplierCount:       6,
+	MinItemsPerSupplier: 2,
+	MaxItemsPerSupplier: 4,
+	DiscountCount:       5,
+	HistoryDepth:        30 * 24 * time.Hour,
+}
+
+func TestGenerate(t *testing.T) {
+	withoutOrders := smallConfig
+	withoutOrders.OrderCount = 0
+
+	single := Config{
+		UserCount:           1,
+		CardsPerUserMin:     1,
+		CardsPerUserMax:     1,
+		AddressesPerUserMin: 1,
+		AddressesPerUserMax: 1,
+		CourierCount:        1,
+		OrderCount:          1,
+		MinItemsPerOrder:    1,
+		MaxItemsPerOrder:    1,
+		MaxItemQuantity:     1,
+		SupplierCount:       1,
+		MinItemsPerSupplier: 1,
+		MaxItemsPerSupplier: 1,
+		DiscountCount:       1,
+		HistoryDepth:        time.Hour,
+	}
+
+	for name, cfg := range map[string]Config{
+		"small":          smallConfig,
+		"without orders": withoutOrders,
+		"single":         single,
+	} {
+		t.Run(name, func(t *testing.T) {
+			db := fakedb.New()
+			if _, err := Generate(queries.New(db), cfg); err != nil {
+				t.Fatalf("generate: %v", err)
+			}
+			if err := checkGenerated(db, cfg); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestGenerateRejectsInvalidConfig(t *testing.T) {
+	noCouriers := smallConfig
+	noCouriers.CourierCount = 0
+	swapped := smallConfig
+	swapped.MinItemsPerOrder, swapped.MaxItemsPerOrder = 5, 1
+	negative := smallConfig
+	negative.DiscountCount = -1
+
+	for name, cfg := range map[string]Config{
+		"orders without couriers": noCouriers,
+		"swapped bounds":          swapped,
+		"negative count":          negative,
+	} {
+		t.Run(name, func(t *testing.T) {
+			db := fakedb.New()
+			if _, err := Generate(queries.New(db), cfg); err == nil {
+				t.Fatal("generate succeeded")
+			}
+			if tables := db.Tables(); len(tables) > 0 {
+				t.Errorf("invalid config filled tables %v", tables)
+			}
+		})
+	}
+}
+
+// checkGenerated returns error describing the first violated invariant of
+// data generated with cfg: row counts within configured bounds, foreign
+// keys referencing generated rows and unique emails.
+func checkGenerated(db *fakedb.DB, cfg Config) error {
+	exact := []struct {
+		table string
+		want  int
+	}{
+		{"users", cfg.UserCount},
+		{"couriers", cfg.CourierCount},
+		{"payments", cfg.OrderCount},
+		{"orders", cfg.OrderCount},
+		{"suppliers", cfg.SupplierCount},
+		{"discounts", cfg.DiscountCount},
+	}
+	for _, e := range exact {
+		if got := db.Count(e.table); got != e.want {
+			return fmt.Errorf("%s: got %d rows, want %d", e.table, got, e.want)
+		}
+	}
+
+	perParent := []struct {
+		table, column string
+		parents       int
+		min, max      int
+	}{
+		{"user_cards", "user_id", cfg.UserCount, cfg.CardsPerUserMin, cfg.CardsPerUserMax},
+		{"user_addresses", "user_id", cfg.UserCount, cfg.AddressesPerUserMin, cfg.AddressesPerUserMax},
+		{"orders_composition", "order_id", cfg.OrderCount, 0, cfg.MaxItemsPerOrder},
+		{"dishes", "supplier_id", cfg.SupplierCount, 0, cfg.MaxItemsPerSupplier},
+		{"commodities", "supplier_id", cfg.SupplierCount, 0, cfg.MaxItemsPerSupplier},
+	}
+	for _, p := range perParent {
+		counts := make(map[int32]int, p.parents)
+		for _, row := range db.Rows(p.table) {
+			id, _ := reference(row.Values[p.column])
+			counts[id]++
+		}
+		for parent := int32(1); parent <= int32(p.parents); parent++ {
+			if n := counts[parent]; n < p.min || n > p.max {
+				return fmt.Errorf("%s: %d rows reference %s %d, want [%d, %d]", p.table, n, p.column, parent, p.min, p.max)
+			}
+		}
+	}
+
+	// Items per order reach the lower bound only if there are enough items
+	// to choose from.
+	if db.Count("dishes") >= cfg.MaxItemsPerOrder && db.Count("commodities") >= cfg.MaxItemsPerOrder {
+		counts := make(map[int32]int, cfg.OrderCount)
+		for _, row := range db.Rows("orders_composition") {
+			counts[row.Values["order_id"].(int32)]++
+		}
+		for order := int32(1); order <= int32(cfg.OrderCount); order++ {
+			if counts[order] < cfg.MinItemsPerOrder {
+				return fmt.Errorf("orders_composition: order %d has %d items, want at least %d", order, counts[order], cfg.MinItemsPerOrder)
+			}
+		}
+	}
+
+	foreignKeys := []struct{ table, column, target string }{
+		{"user_cards", "user_id", "users"},
+		{"user_addresses", "user_id", "users"},
+		{"payments", "card_id", "user_cards"},
+		{"orders", "user_id", "users"},
+		{"orders", "courier_id", "couriers"},
+		{"orders", "payment_id", "payments"},
+		{"orders_composition", "order_id", "orders"},
+		{"orders_composition", "dish_id", "dishes"},
+		{"orders_composition", "commodity_id", "commodities"},
+		{"order_status_history", "order_id", "orders"},
+		{"dishes", "supplier_id", "suppliers"},
+		{"commodities", "supplier_id", "suppliers"},
+		{"categories_to_targets", "category_id", "categories"},
+		{"categories_to_targets", "dish_id", "dishes"},
+		{"categories_to_targets", "commodity_id", "commodities"},
+		{"discount_to_targets", "discount_id", "discounts"},
+		{"discount_to_targets", "dish_id", "dishes"},
+		{"discount_to_targets", "commodity_id", "commodities"},
+	}
+	for _, fk := range foreignKeys {
+		n := int32(db.Count(fk.target))
+		for _, row := range db.Rows(fk.table) {
+			id, ok := reference(row.Values[fk.column])
+			if ok && (id < 1 || id > n) {
+				return fmt.Errorf("%s %d: %s %d is out of generated range [1, %d]", fk.table, row.ID, fk.column, id, n)
+			}
+		}
+	}
+
+	emails := make(map[string]int32, cfg.UserCount)
+	for _, row := range db.Rows("users") {
+		email := row.Values["email"].(string)
+		if other, ok := emails[email]; ok {
+			return fmt.Errorf("users %d and %d have the same email %q", other, row.ID, email)
+		}
+		emails[email] = row.ID
+	}
+	return nil
+}
+
+// reference returns id stored in foreign key column and whether it is set.
+func reference(value any) (int32, bool) {
+	switch v := value.(type) {
+	case int32:
+		return v, true
+	case pgtype.Int4:
+		return v.Int32, v.Valid
+	}
+	panic(fmt.Sprintf("unexpected foreign key value %#v", value))
+}
@@ -6,9 +6,11 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"hash/crc64"
 	"math/big"
 	"math/rand/v2"
+	"slices"
 	"strings"
 	"time"
 
@@ -16,6 +18,7 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 
 	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+	"github.com/LeKSuS-04/mephi-db/internal/lifecycle"
 )
 
 //go:embed data.json
@@ -52,7 +55,6 @@ type PredefinedData struct {
 
 var predefinedData PredefinedData
 var categories []string
-var usedEmails = make(map[string]struct{}, UserCount)
 
 func init() {
 	var err error
@@ -74,7 +76,19 @@ func init() {
 	}
 }
 
-func randomUser() queries.CreateUsersParams {
+// Ingredients returns unique ingredients of predefined dishes, sorted.
+func Ingredients() []string {
+	var ingredients []string
+	for _, dish := range predefinedData.Dishes {
+		ingredients = append(ingredients, dish.Ingredients...)
+	}
+	slices.Sort(ingredients)
+	return slices.Compact(ingredients)
+}
+
+// randomUser returns user with email not present in usedEmails and adds it
+// there, since emails are unique.
+func randomUser(usedEmails map[string]struct{}) queries.CreateUsersParams {
 	password := gofakeit.Password(true, true, true, true, true, 32)
 	h := sha256.Sum256([]byte(password))
 	hash := hex.EncodeToString(h[:])
@@ -96,10 +110,7 @@ func randomUser() queries.CreateUsersParams {
 			String: gofakeit.LastName(),
 			Valid:  true,
 		},
-		Email: pgtype.Text{
-			String: email,
-			Valid:  true,
-		},
+		Email: email,
 		Phone: pgtype.Text{
 			String: gofakeit.Phone(),
 			Valid:  true,
@@ -138,7 +149,7 @@ func randomCourier() queries.CreateCourieresParams {
 	}
 }
 
-func randomPayment(cardIDs []int32) queries.CreatePaymentsParams {
+func randomPayment(cardIDs []int32, historyDepth time.Duration) queries.CreatePaymentsParams {
 	method := choose([]string{"cash", "card", "online", "online", "online", "online", "online"})
 	status := "successful"
 	if method == "online" && rand.IntN(20) == 0 {
@@ -155,29 +166,15 @@ func randomPayment(cardIDs []int32) queries.CreatePaymentsParams {
 		Status: status,
 		CardID: pgtype.Int4{Int32: cardID, Valid: cardID != -1},
 		Timestamp: pgtype.Timestamp{
-			Time:  gofakeit.DateRange(time.Now().Add(-365*24*time.Hour), time.Now()),
+			Time:  gofakeit.DateRange(time.Now().Add(-historyDepth), time.Now()),
 			Valid: true,
 		},
 	}
 }
 
-func randomOrder(paymentID int32, userIDs, courierIDs []int32) queries.CreateOrdersParams {
-	var status string
-	rng := rand.IntN(100)
-	switch {
-	case rng == 0:
-		status = "canceled"
-	case rng < 3:
-		status = "in_progress"
-	default:
-		status = "delivered"
-	}
-
+func randomOrder(userID, paymentID int32, courierIDs []int32, historyDepth time.Duration) queries.CreateOrdersParams {
 	return queries.CreateOrdersParams{
-		UserID: pgtype.Int4{
-			Int32: choose(userIDs),
-			Valid: true,
-		},
+		UserID: userID,
 		SourceAddress: pgtype.Text{
 			String: gofakeit.Address().Address,
 			Valid:  true,
@@ -190,12 +187,9 @@ func randomOrder(paymentID int32, userIDs, courierIDs []int32) queries.CreateOrd
 			Int32: choose(courierIDs),
 			Valid: true,
 		},
-		Status: pgtype.Text{
-			String: status,
-			Valid:  true,
-		},
+		Status: string(lifecycle.Created),
 		Timestamp: pgtype.Timestamp{
-			Time:  gofakeit.DateRange(time.Now().Add(-365*24*time.Hour), time.Now()),
+			Time:  gofakeit.DateRange(time.Now().Add(-historyDepth), time.Now()),
 			Valid: true,
 		},
 		PaymentID: pgtype.Int4{
@@ -205,6 +199,69 @@ func randomOrder(paymentID int32, userIDs, courierIDs []int32) queries.CreateOrd
 	}
 }
 
+// randomHistory simulates lifecycle of order created at given time. Most
+// orders get delivered, few are canceled or refunded, and the most recent
+// ones are still in progress, since transitions after now are cut off.
+// Orders whose payment failed are never paid and can only be canceled.
+func randomHistory(createdAt, now time.Time, paymentFailed bool) []lifecycle.Event {
+	history := []lifecycle.Event{{Status: lifecycle.Created, Timestamp: createdAt}}
+	status, at := lifecycle.Created, createdAt
+	for {
+		next, delay := randomTransition(status)
+		if paymentFailed && status == lifecycle.Created {
+			next, delay = lifecycle.Canceled, randomMinutes(1, 30)
+		}
+		if next == "" {
+			return history
+		}
+		at = at.Add(delay)
+		if at.After(now) {
+			return history
+		}
+		status = next
+		history = append(history, lifecycle.Event{Status: status, Timestamp: at})
+	}
+}
+
+// randomTransition returns status order moves to from s and how long it
+// takes, or empty status if order stays in s.
+func randomTransition(s lifecycle.Status) (lifecycle.Status, time.Duration) {
+	rng := rand.IntN(100)
+	switch s {
+	case lifecycle.Created:
+		if rng < 2 {
+			return lifecycle.Canceled, randomMinutes(1, 30)
+		}
+		return lifecycle.Paid, randomMinutes(1, 10)
+	case lifecycle.Paid:
+		if rng < 1 {
+			return lifecycle.Refunded, randomMinutes(1, 15)
+		}
+		return lifecycle.Accepted, randomMinutes(1, 15)
+	case lifecycle.Accepted:
+		return lifecycle.PickedUp, randomMinutes(10, 40)
+	case lifecycle.PickedUp:
+		if rng < 1 {
+			return lifecycle.Refunded, randomMinutes(30, 120)
+		}
+		return lifecycle.Delivered, randomMinutes(10, 60)
+	case lifecycle.Delivered:
+		if rng < 1 {
+			return lifecycle.Refunded, randomMinutes(60, 24*60)
+		}
+	}
+	return "", 0
+}
+
+func randomMinutes(lo, hi int) time.Duration {
+	return time.Duration(randomCount(lo, hi)) * time.Minute
+}
+
+// randomCount returns number in [lo, hi], both bounds included.
+func randomCount(lo, hi int) int {
+	return rand.IntN(hi-lo+1) + lo
+}
+
 func randomSupplier() queries.CreateSuppliersParams {
 	return queries.CreateSuppliersParams{
 		Name: gofakeit.Company(),
@@ -301,6 +358,15 @@ func randomDiscount() queries.CreateDiscountsParams {
 	}
 }
 
+// randomQuantity mostly returns 1, as people rarely order several units of
+// the same item.
+func randomQuantity(maxQuantity int) int32 {
+	if rand.IntN(4) != 0 {
+		return 1
+	}
+	return rand.Int32N(int32(maxQuantity)) + 1
+}
+
 func randomRating() pgtype.Numeric {
 	return pgtype.Numeric{
 		Int:   big.NewInt(rand.Int64N(400) + 100),
@@ -313,12 +379,36 @@ func choose[T any](values []T) T {
 	return values[rand.IntN(len(values))]
 }
 
+// chooseUniq returns one of values which index is not in alreadyChosen yet,
+// every remaining one being equally likely. Callers must not ask for more
+// values than there are, it panics instead of looking for a free one forever.
 func chooseUniq[T any](values []T, alreadyChosen map[int]struct{}) T {
-	idx := rand.IntN(len(values))
-	_, ok := alreadyChosen[idx]
-	for ok {
-		idx = (idx + 1) % len(values)
-		_, ok = alreadyChosen[idx]
+	free := len(values) - len(alreadyChosen)
+	if free <= 0 {
+		panic(fmt.Sprintf("choose unique value: all %d values are already chosen", len(values)))
+	}
+
+	var idx int
+	if free > len(values)/2 {
+		// Most values are free, so drawing until a free one is found takes
+		// less than two draws on average.
+		idx = rand.IntN(len(values))
+		for _, ok := alreadyChosen[idx]; ok; _, ok = alreadyChosen[idx] {
+			idx = rand.IntN(len(values))
+		}
+	} else {
+		// Otherwise draws would mostly hit chosen values, so free ones are
+		// counted until the drawn one.
+		nth := rand.IntN(free)
+		for idx = 0; ; idx++ {
+			if _, ok := alreadyChosen[idx]; ok {
+				continue
+			}
+			if nth == 0 {
+				break
+			}
+			nth--
+		}
 	}
 	alreadyChosen[idx] = struct{}{}
 	return values[idx]
@@ -0,0 +1,81 @@
+package gen
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+const draws = 10_000
+
+func TestRandomCount(t *testing.T) {
+	seen := make(map[int]int)
+	for range draws {
+		seen[randomCount(2, 5)]++
+	}
+	if got := slices.Sorted(maps.Keys(seen)); !slices.Equal(got, []int{2, 3, 4, 5}) {
+		t.Errorf("drew %v, want every number of [2, 5]", got)
+	}
+
+	for range 100 {
+		if n := randomCount(3, 3); n != 3 {
+			t.Fatalf("randomCount(3, 3) = %d", n)
+		}
+	}
+}
+
+func TestChooseUniq(t *testing.T) {
+	values := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	chosen := make(map[int]struct{})
+	for range values {
+		chooseUniq(values, chosen)
+	}
+	if len(chosen) != len(values) {
+		t.Errorf("chose %d of %d values", len(chosen), len(values))
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("choosing from exhausted values did not panic")
+		}
+	}()
+	chooseUniq(values, chosen)
+}
+
+// TestChooseUniqUniform checks that every free value is equally likely, both
+// when most values are free and when most are chosen. Values right after
+// chosen ones must not be favored.
+func TestChooseUniqUniform(t *testing.T) {
+	values := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	for name, chosen := range map[string][]int{
+		"mostly free":   {1, 2, 3},
+		"mostly chosen": {0, 1, 2, 4, 5, 6, 7},
+	} {
+		t.Run(name, func(t *testing.T) {
+			counts := make(map[int]int)
+			for range draws {
+				alreadyChosen := make(map[int]struct{})
+				for _, idx := range chosen {
+					alreadyChosen[idx] = struct{}{}
+				}
+				counts[chooseUniq(values, alreadyChosen)]++
+			}
+
+			free := len(values) - len(chosen)
+			want := draws / free
+			for _, v := range values {
+				if slices.Contains(chosen, v) {
+					if counts[v] != 0 {
+						t.Errorf("chosen value %d was drawn %d times", v, counts[v])
+					}
+					continue
+				}
+				// Allowed deviation is many standard deviations wide, so the
+				// test is not flaky.
+				if counts[v] < want*3/4 || counts[v] > want*5/4 {
+					t.Errorf("value %d was drawn %d times, want about %d", v, counts[v], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/fakedb"
+	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+	"github.com/LeKSuS-04/mephi-db/internal/lifecycle"
+)
+
+const generateTimeout = 30 * time.Second
+
+// TestGenerateProperties generates data with random configs and checks its
+// invariants. Failing config is shrunk to a minimal one still failing, which
+// is reported along with the seed it was derived from.
+func TestGenerateProperties(t *testing.T) {
+	seeds := 50
+	if testing.Short() {
+		seeds = 10
+	}
+	for seed := range uint64(seeds) {
+		cfg := randomConfig(rand.New(rand.NewPCG(seed, seed)))
+		err := generateAndCheck(cfg)
+		if err == nil {
+			continue
+		}
+		minimal := shrink(cfg, func(c Config) bool {
+			// Generation is random, so a config is considered passing only
+			// if it passes several times in a row.
+			for range 3 {
+				if generateAndCheck(c) != nil {
+					return true
+				}
+			}
+			return false
+		})
+		t.Fatalf("seed %d: %v\nconfig: %+v\nminimal failing config: %+v\nminimal config error: %v",
+			seed, err, cfg, minimal, generateAndCheck(minimal))
+	}
+}
+
+func TestShrink(t *testing.T) {
+	fails := func(c Config) bool { return c.OrderCount >= 5 && c.UserCount >= 3 }
+	got := shrink(smallConfig, fails)
+
+	if !fails(got) {
+		t.Fatalf("shrunk config %+v does not fail", got)
+	}
+	if got.OrderCount != 5 || got.UserCount != 3 {
+		t.Errorf("got %d orders and %d users, want 5 and 3", got.OrderCount, got.UserCount)
+	}
+	for i := range len(fields(&got)) {
+		candidate := got
+		*fields(&candidate)[i]--
+		if candidate.Validate() == nil && fails(candidate) {
+			t.Errorf("shrunk config %+v is not minimal, %+v fails too", got, candidate)
+		}
+	}
+}
+
+func randomConfig(r *rand.Rand) Config {
+	between := func(lo, hi int) int { return lo + r.IntN(hi-lo+1) }
+	c := Config{
+		UserCount:           between(1, 30),
+		CardsPerUserMin:     between(1, 3),
+		AddressesPerUserMin: between(0, 3),
+		CourierCount:        between(1, 5),
+		OrderCount:          between(0, 60),
+		MinItemsPerOrder:    between(0, 5),
+		MaxItemQuantity:     between(1, 5),
+		SupplierCount:       between(0, 10),
+		MinItemsPerSupplier: between(0, 5),
+		DiscountCount:       between(0, 10),
+		HistoryDepth:        time.Duration(between(1, 60*24)) * time.Hour,
+	}
+	c.CardsPerUserMax = c.CardsPerUserMin + between(0, 3)
+	c.AddressesPerUserMax = c.AddressesPerUserMin + between(0, 3)
+	c.MaxItemsPerOrder = c.MinItemsPerOrder + between(0, 5)
+	c.MaxItemsPerSupplier = c.MinItemsPerSupplier + between(0, 10)
+	return c
+}
+
+// fields returns pointers to every amount of c, in the order shrink tries
+// to reduce them.
+func fields(c *Config) []*int {
+	return []*int{
+		&c.OrderCount,
+		&c.UserCount,
+		&c.CourierCount,
+		&c.SupplierCount,
+		&c.DiscountCount,
+		&c.CardsPerUserMax,
+		&c.CardsPerUserMin,
+		&c.AddressesPerUserMax,
+		&c.AddressesPerUserMin,
+		&c.MaxItemsPerOrder,
+		&c.MinItemsPerOrder,
+		&c.MaxItemsPerSupplier,
+		&c.MinItemsPerSupplier,
+		&c.MaxItemQuantity,
+	}
+}
+
+// shrink reduces amounts of failing cfg while it keeps failing: every amount
+// is halved, or decremented if halving makes it pass, until none of them can
+// be reduced.
+func shrink(cfg Config, fails func(Config) bool) Config {
+	for changed := true; changed; {
+		changed = false
+		for i := range len(fields(&cfg)) {
+			current := *fields(&cfg)[i]
+			for _, reduced := range []int{current / 2, current - 1} {
+				if reduced < 0 || reduced == current {
+					continue
+				}
+				candidate := cfg
+				*fields(&candidate)[i] = reduced
+				if candidate.Validate() != nil || !fails(candidate) {
+					continue
+				}
+				cfg, changed = candidate, true
+				break
+			}
+		}
+	}
+	return cfg
+}
+
+// generateAndCheck fills a fresh fake database using cfg and checks
+// invariants of the result.
+func generateAndCheck(cfg Config) error {
+	db := fakedb.New()
+	done := make(chan error, 1)
+	go func() {
+		_, err := Generate(queries.New(db), cfg)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("generate: %w", err)
+		}
+	case <-time.After(generateTimeout):
+		return fmt.Errorf("generate did not finish in %s", generateTimeout)
+	}
+
+	return errors.Join(checkGenerated(db, cfg), checkConsistency(db))
+}
+
+// checkConsistency returns error describing the first violated invariant
+// which does not depend on config: items and discount targets are not
+// repeated, cards pay for orders of their owners and status histories
+// follow the order lifecycle.
+func checkConsistency(db *fakedb.DB) error {
+	pairs := []struct{ table, parent, child string }{
+		{"orders_composition", "order_id", "dish_id"},
+		{"orders_composition", "order_id", "commodity_id"},
+		{"discount_to_targets", "discount_id", "dish_id"},
+		{"discount_to_targets", "discount_id", "commodity_id"},
+		{"dishes", "supplier_id", "name"},
+		{"commodities", "supplier_id", "name"},
+	}
+	for _, p := range pairs {
+		seen := make(map[[2]any]struct{})
+		for _, row := range db.Rows(p.table) {
+			child := row.Values[p.child]
+			if id, ok := child.(pgtype.Int4); ok {
+				if !id.Valid {
+					continue
+				}
+				child = id.Int32
+			}
+			key := [2]any{row.Values[p.parent], child}
+			if _, ok := seen[key]; ok {
+				return fmt.Errorf("%s: %s %v is repeated for %s %v", p.table, p.child, child, p.parent, key[0])
+			}
+			seen[key] = struct{}{}
+		}
+	}
+
+	cardOwners := make(map[int32]int32)
+	for _, row := range db.Rows("user_cards") {
+		cardOwners[row.ID] = row.Values["user_id"].(int32)
+	}
+	paymentCards := make(map[int32]pgtype.Int4)
+	for _, row := range db.Rows("payments") {
+		paymentCards[row.ID] = row.Values["card_id"].(pgtype.Int4)
+	}
+	for _, row := range db.Rows("orders") {
+		card := paymentCards[row.Values["payment_id"].(pgtype.Int4).Int32]
+		if user := row.Values["user_id"].(int32); card.Valid && cardOwners[card.Int32] != user {
+			return fmt.Errorf("order %d of user %d is paid with card %d of user %d", row.ID, user, card.Int32, cardOwners[card.Int32])
+		}
+	}
+
+	histories := make(map[int32][]lifecycle.Event)
+	for _, row := range db.Rows("order_status_history") {
+		order := row.Values["order_id"].(int32)
+		histories[order] = append(histories[order], lifecycle.Event{
+			Status:    lifecycle.Status(row.Values["status"].(string)),
+			Timestamp: row.Values["timestamp"].(pgtype.Timestamp).Time,
+		})
+	}
+	for order, history := range histories {
+		if err := lifecycle.Validate(history); err != nil {
+			return fmt.Errorf("history of order %d: %w", order, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package golden
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+	"github.com/LeKSuS-04/mephi-db/internal/fixtures"
+	"github.com/LeKSuS-04/mephi-db/internal/reports"
+)
+
+// Fixture is name of dataset golden results are computed on.
+const Fixture = "seed"
+
+// Params holds values of required report parameters, chosen so that reports
+// return non-empty results on the fixture.
+var Params = map[string]string{
+	"commodity_name": "Bread",
+	"ingredient":     "meat",
+	"street":         "Tverskaya",
+	"prefix":         "Iv",
+}
+
+type Status string
+
+const (
+	Match    Status = "match"
+	Mismatch Status = "mismatch"
+	Missing  Status = "missing"
+	Updated  Status = "updated"
+)
+
+type Outcome struct {
+	Report string `json:"report"`
+	Status Status `json:"status"`
+	// Diff lists golden lines absent from actual result prefixed with "-",
+	// and actual lines absent from golden file prefixed with "+".
+	Diff []string `json:"diff,omitempty"`
+}
+
+// Run loads fixture in a transaction which is rolled back afterwards, runs
+// reports on it and compares results with golden files <dir>/<report>.json.
+// With update set, golden files are overwritten instead.
+//
+// Rows are sorted before comparison, so results of queries without ORDER BY
+// are stable; as a consequence, ordering itself is not verified. Reports
+// relative to current date (e.g. orders of the last week) return nothing on
+// the fixture.
+func Run(ctx context.Context, pg *pgxpool.Pool, dir string, rs []reports.Report, update bool) ([]Outcome, error) {
+	found, err := fixtures.Find([]string{Fixture})
+	if err != nil {
+		return nil, err
+	}
+	if update {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create golden dir: %w", err)
+		}
+	}
+
+	var outcomes []Outcome
+	err = pgx.BeginFunc(ctx, pg, func(tx pgx.Tx) error {
+		if err := fixtures.Load(ctx, tx, found[0]); err != nil {
+			return err
+		}
+		q := queries.New(tx)
+		for _, r := range rs {
+			log.Printf("Checking report %q", r.Name)
+			actual, err := render(ctx, q, r)
+			if err != nil {
+				return fmt.Errorf("run report %q: %w", r.Name, err)
+			}
+			outcome, err := check(filepath.Join(dir, r.Name+".json"), actual, update)
+			if err != nil {
+				return err
+			}
+			outcome.Report = r.Name
+			outcomes = append(outcomes, *outcome)
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		return nil, err
+	}
+	return outcomes, nil
+}
+
+var errRollback = errors.New("rollback")
+
+func render(ctx context.Context, q *queries.Queries, r reports.Report) ([]byte, error) {
+	args, err := r.ParseArgs(r.SelectParams(Params))
+	if err != nil {
+		return nil, err
+	}
+	result, err := r.Run(ctx, q, args)
+	if err != nil {
+		return nil, err
+	}
+
+	type keyed struct {
+		key string
+		row []any
+	}
+	rows := make([]keyed, len(result.Rows))
+	for i, row := range result.Rows {
+		key, err := json.Marshal(row)
+		if err != nil {
+			return nil, err
+		}
+		rows[i] = keyed{key: string(key), row: row}
+	}
+	slices.SortStableFunc(rows, func(a, b keyed) int { return strings.Compare(a.key, b.key) })
+	for i, row := range rows {
+		result.Rows[i] = row.row
+	}
+
+	var buf bytes.Buffer
+	if err := result.WriteJSON(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func check(path string, actual []byte, update bool) (*Outcome, error) {
+	expected, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		if !update {
+			return &Outcome{Status: Missing}, nil
+		}
+	case err != nil:
+		return nil, fmt.Errorf("read golden file: %w", err)
+	case bytes.Equal(expected, actual):
+		return &Outcome{Status: Match}, nil
+	case !update:
+		return &Outcome{Status: Mismatch, Diff: diff(expected, actual)}, nil
+	}
+
+	if err := os.WriteFile(path, actual, 0o644); err != nil {
+		return nil, fmt.Errorf("write golden file: %w", err)
+	}
+	return &Outcome{Status: Updated}, nil
+}
+
+// diff compares outputs line by line as multisets, which is enough for
+// sorted rows.
+func diff(expected, actual []byte) []string {
+	counts := make(map[string]int)
+	for _, line := range strings.Split(string(actual), "\n") {
+		counts[strings.TrimSuffix(line, ",")]++
+	}
+	var lines []string
+	for _, line := range strings.Split(string(expected), "\n") {
+		line = strings.TrimSuffix(line, ",")
+		if counts[line] > 0 {
+			counts[line]--
+			continue
+		}
+		lines = append(lines, "- "+line)
+	}
+	for _, line := range strings.Split(string(actual), "\n") {
+		line = strings.TrimSuffix(line, ",")
+		if counts[line] > 0 {
+			counts[line]--
+			lines = append(lines, "+ "+line)
+		}
+	}
+	return lines
+}
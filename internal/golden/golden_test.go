@@ -0,0 +1,52 @@
+package golden
+
+import (
+	"context"
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/LeKSuS-04/mephi-db/internal/reports"
+	"github.com/LeKSuS-04/mephi-db/internal/tempdb/tempdbtest"
+)
+
+var update = flag.Bool("update", false, "overwrite golden files with actual results")
+
+const (
+	schemaPath = "../../sql/schema.sql"
+	goldenDir  = "testdata"
+)
+
+// TestReports compares results of every report on seed fixture with golden
+// files in testdata. Reports are run against a disposable postgres started
+// from locally installed binaries, so the test is skipped if there are none
+// or if it is run by root.
+func TestReports(t *testing.T) {
+	server := tempdbtest.Start(t, schemaPath)
+	outcomes, err := Run(context.Background(), server.Pool, goldenDir, reports.All(), *update)
+	if err != nil {
+		t.Fatalf("check golden results: %v", err)
+	}
+	for _, o := range outcomes {
+		switch o.Status {
+		case Match:
+		case Updated:
+			t.Logf("%s: golden file updated", o.Report)
+		case Missing:
+			t.Errorf("%s: golden file is missing, run with -update to create it", o.Report)
+		default:
+			t.Errorf("%s: %s, run with -update to accept\n  %s", o.Report, o.Status, strings.Join(o.Diff, "\n  "))
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	expected := []byte("[\n  [1, \"a\"],\n  [2, \"b\"],\n  [2, \"b\"]\n]\n")
+	actual := []byte("[\n  [1, \"a\"],\n  [2, \"b\"],\n  [3, \"c\"]\n]\n")
+
+	got := diff(expected, actual)
+	want := []string{`-   [2, "b"]`, `+   [3, "c"]`}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("diff = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,239 @@
+package indexes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/LeKSuS-04/mephi-db/internal/bench"
+	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+	"github.com/LeKSuS-04/mephi-db/internal/reports"
+)
+
+type Verdict string
+
+const (
+	// Useful indexes are used by some report which gets noticeably slower
+	// without them.
+	Useful Verdict = "useful"
+	// NoImpact indexes are used by some reports, but hiding them doesn't slow
+	// those reports down, i.e. other indexes or scans do as well.
+	NoImpact Verdict = "no-impact"
+	// Unused indexes are not used by any report.
+	Unused Verdict = "unused"
+)
+
+type Options struct {
+	// Params are used for every report having parameter with such name.
+	// Reports with required parameters missing from here are skipped.
+	Params map[string]string
+	// Iterations is amount of EXPLAIN ANALYZE runs per measurement, median
+	// execution time is taken.
+	Iterations int
+	// Timeout limits single statement without index. Timed out statements
+	// are treated as infinitely slower.
+	Timeout time.Duration
+	// Threshold is relative slowdown considered significant, e.g. 0.2 for
+	// 20%.
+	Threshold float64
+	// MinDelta is absolute slowdown in milliseconds below which differences
+	// are treated as noise.
+	MinDelta float64
+}
+
+type Impact struct {
+	Index   Index          `json:"index"`
+	Verdict Verdict        `json:"verdict"`
+	Reports []ReportImpact `json:"reports"`
+}
+
+// ReportImpact compares execution of a report referencing index table with
+// index present and hidden. Times are in milliseconds.
+type ReportImpact struct {
+	Report       string   `json:"report"`
+	UsesIndex    bool     `json:"uses_index"`
+	With         float64  `json:"with"`
+	Without      float64  `json:"without"`
+	TimedOut     bool     `json:"timed_out,omitempty"`
+	IndexesAfter []string `json:"indexes_after"`
+}
+
+// Slowdown is relative change of execution time after hiding the index.
+func (r ReportImpact) Slowdown() float64 {
+	if r.With == 0 {
+		return 0
+	}
+	return (r.Without - r.With) / r.With
+}
+
+type statement struct {
+	report string
+	stmt   reports.Statement
+}
+
+type measurement struct {
+	executionTime float64
+	indexes       []string
+	timedOut      bool
+}
+
+// Measure runs reports referencing table of each index twice: as is, and
+// with the index dropped inside a transaction which is rolled back
+// afterwards. Dropping takes exclusive lock on the table, so it should not
+// be run against database serving other clients.
+func Measure(ctx context.Context, pg *pgxpool.Pool, idxs []Index, rs []reports.Report, opts Options) ([]Impact, error) {
+	var stmts []statement
+	for _, r := range rs {
+		args, err := r.ParseArgs(r.SelectParams(opts.Params))
+		if err != nil {
+			log.Printf("Skipping report %q: %v", r.Name, err)
+			continue
+		}
+		stmt, err := r.Statement(args)
+		if err != nil {
+			return nil, fmt.Errorf("build statement of %q: %w", r.Name, err)
+		}
+		stmts = append(stmts, statement{report: r.Name, stmt: stmt})
+	}
+
+	baseline := make(map[string]measurement)
+	impacts := make([]Impact, 0, len(idxs))
+	for _, idx := range idxs {
+		related := referencing(stmts, idx.Table)
+		for _, s := range related {
+			if _, ok := baseline[s.report]; ok {
+				continue
+			}
+			log.Printf("Measuring report %q", s.report)
+			m, err := measure(ctx, pg, s.stmt, opts)
+			if err != nil {
+				return nil, fmt.Errorf("measure %q: %w", s.report, err)
+			}
+			baseline[s.report] = m
+		}
+
+		log.Printf("Measuring impact of index %q", idx.Name)
+		impact, err := measureWithout(ctx, pg, idx, related, baseline, opts)
+		if err != nil {
+			return nil, fmt.Errorf("measure impact of %q: %w", idx.Name, err)
+		}
+		impacts = append(impacts, *impact)
+	}
+	return impacts, nil
+}
+
+func measureWithout(
+	ctx context.Context,
+	pg *pgxpool.Pool,
+	idx Index,
+	related []statement,
+	baseline map[string]measurement,
+	opts Options,
+) (*Impact, error) {
+	impact := &Impact{Index: idx, Verdict: Unused}
+
+	tx, err := pg.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "DROP INDEX "+pgx.Identifier{idx.Name}.Sanitize()); err != nil {
+		return nil, fmt.Errorf("drop index: %w", err)
+	}
+	if opts.Timeout > 0 {
+		timeout := fmt.Sprintf("SET LOCAL statement_timeout = %d", opts.Timeout.Milliseconds())
+		if _, err := tx.Exec(ctx, timeout); err != nil {
+			return nil, fmt.Errorf("set statement timeout: %w", err)
+		}
+	}
+
+	for _, s := range related {
+		with := baseline[s.report]
+		without, err := measure(ctx, tx, s.stmt, opts)
+		if err != nil {
+			return nil, fmt.Errorf("measure %q: %w", s.report, err)
+		}
+
+		r := ReportImpact{
+			Report:       s.report,
+			UsesIndex:    slices.Contains(with.indexes, idx.Name),
+			With:         with.executionTime,
+			Without:      without.executionTime,
+			TimedOut:     without.timedOut,
+			IndexesAfter: without.indexes,
+		}
+		impact.Reports = append(impact.Reports, r)
+
+		if !r.UsesIndex {
+			continue
+		}
+		significant := r.TimedOut ||
+			(r.Slowdown() >= opts.Threshold && r.Without-r.With >= opts.MinDelta)
+		switch {
+		case significant:
+			impact.Verdict = Useful
+		case impact.Verdict == Unused:
+			impact.Verdict = NoImpact
+		}
+	}
+	return impact, nil
+}
+
+// measure explains statement given amount of times in a savepoint, so that
+// cancelled statement doesn't abort outer transaction.
+func measure(ctx context.Context, db queries.DBTX, stmt reports.Statement, opts Options) (measurement, error) {
+	var m measurement
+	times := make([]float64, 0, opts.Iterations)
+	for range max(opts.Iterations, 1) {
+		plan, err := explain(ctx, db, stmt)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "57014" {
+			m.timedOut = true
+			return m, nil
+		}
+		if err != nil {
+			return m, err
+		}
+		times = append(times, plan.ExecutionTime)
+		m.indexes = plan.Indexes
+	}
+	slices.Sort(times)
+	m.executionTime = times[len(times)/2]
+	return m, nil
+}
+
+func explain(ctx context.Context, db queries.DBTX, stmt reports.Statement) (*bench.Plan, error) {
+	tx, ok := db.(pgx.Tx)
+	if !ok {
+		return bench.Explain(ctx, db, stmt)
+	}
+
+	var plan *bench.Plan
+	err := pgx.BeginFunc(ctx, tx, func(sp pgx.Tx) error {
+		var err error
+		plan, err = bench.Explain(ctx, sp, stmt)
+		return err
+	})
+	return plan, err
+}
+
+// referencing returns statements mentioning table by name.
+func referencing(stmts []statement, table string) []statement {
+	re := regexp.MustCompile(`\b` + regexp.QuoteMeta(table) + `\b`)
+	var related []statement
+	for _, s := range stmts {
+		if re.MatchString(s.stmt.SQL) {
+			related = append(related, s)
+		}
+	}
+	return related
+}
@@ -0,0 +1,92 @@
+package indexes
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Index is a secondary index which may be dropped without breaking any
+// constraint.
+type Index struct {
+	Name       string   `json:"name"`
+	Table      string   `json:"table"`
+	Method     string   `json:"method"`
+	Unique     bool     `json:"unique"`
+	Keys       []string `json:"keys"`
+	Definition string   `json:"definition"`
+	// Scans is amount of index scans since statistics reset, as reported by
+	// pg_stat_user_indexes. It is zero for partitioned indexes.
+	Scans int64 `json:"scans"`
+	// RedundantWith lists indexes making this one structurally unnecessary:
+	// ones with the same keys, or btree ones having its keys as a prefix.
+	RedundantWith []string `json:"redundant_with,omitempty"`
+}
+
+// List returns secondary indexes of tables in public schema. Primary keys,
+// indexes backing constraints and indexes of individual partitions are
+// skipped.
+func List(ctx context.Context, pg *pgxpool.Pool) ([]Index, error) {
+	rows, err := pg.Query(ctx, `
+		SELECT
+			i.relname,
+			t.relname,
+			am.amname,
+			ix.indisunique,
+			ARRAY(
+				SELECT pg_get_indexdef(ix.indexrelid, k, true)
+				FROM generate_series(1, ix.indnkeyatts) k
+				ORDER BY k
+			),
+			pg_get_indexdef(ix.indexrelid),
+			COALESCE(s.idx_scan, 0)
+		FROM pg_index ix
+		JOIN pg_class i ON i.oid = ix.indexrelid
+		JOIN pg_class t ON t.oid = ix.indrelid
+		JOIN pg_am am ON am.oid = i.relam
+		LEFT JOIN pg_stat_user_indexes s ON s.indexrelid = ix.indexrelid
+		WHERE t.relnamespace = 'public'::regnamespace
+			AND NOT t.relispartition
+			AND NOT ix.indisprimary
+			AND NOT EXISTS (SELECT 1 FROM pg_constraint c WHERE c.conindid = ix.indexrelid)
+		ORDER BY t.relname, i.relname
+	`)
+	if err != nil {
+		return nil, err
+	}
+	indexes, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (Index, error) {
+		var idx Index
+		err := row.Scan(&idx.Name, &idx.Table, &idx.Method, &idx.Unique, &idx.Keys, &idx.Definition, &idx.Scans)
+		return idx, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list indexes: %w", err)
+	}
+
+	for i := range indexes {
+		for j := range indexes {
+			if i != j && covers(indexes[j], indexes[i]) {
+				indexes[i].RedundantWith = append(indexes[i].RedundantWith, indexes[j].Name)
+			}
+		}
+	}
+	return indexes, nil
+}
+
+// covers reports whether index a can serve every lookup index b serves.
+// Operator classes are not compared, so the result is a hint rather than a
+// proof: e.g. GIN trigram and BTREE text_pattern_ops indexes on the same
+// column are reported as covering each other.
+func covers(a, b Index) bool {
+	if a.Table != b.Table || b.Unique {
+		return false
+	}
+	if slices.Equal(a.Keys, b.Keys) {
+		return true
+	}
+	return a.Method == "btree" && (b.Method == "btree" || b.Method == "hash") &&
+		len(b.Keys) < len(a.Keys) && slices.Equal(a.Keys[:len(b.Keys)], b.Keys)
+}
@@ -0,0 +1,155 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+)
+
+type Status string
+
+const (
+	Created   Status = "created"
+	Paid      Status = "paid"
+	Accepted  Status = "accepted"
+	PickedUp  Status = "picked_up"
+	Delivered Status = "delivered"
+	// Canceled is the end of orders which were never paid.
+	Canceled Status = "canceled"
+	// Refunded is the end of paid orders which were canceled or complained
+	// about after delivery.
+	Refunded Status = "refunded"
+)
+
+var (
+	ErrInvalidTransition = errors.New("invalid status transition")
+	// ErrBackdated is returned for transitions preceding the latest status
+	// of order, which would make its history go back in time.
+	ErrBackdated = errors.New("transition precedes latest status")
+)
+
+var transitions = map[Status][]Status{
+	Created:   {Paid, Canceled},
+	Paid:      {Accepted, Refunded},
+	Accepted:  {PickedUp, Refunded},
+	PickedUp:  {Delivered, Refunded},
+	Delivered: {Refunded},
+	Canceled:  nil,
+	Refunded:  nil,
+}
+
+// Statuses returns every status in lifecycle order.
+func Statuses() []Status {
+	return []Status{Created, Paid, Accepted, PickedUp, Delivered, Canceled, Refunded}
+}
+
+func Parse(s string) (Status, error) {
+	if _, ok := transitions[Status(s)]; !ok {
+		return "", fmt.Errorf("unknown order status %q", s)
+	}
+	return Status(s), nil
+}
+
+// Next returns statuses order may move to from s.
+func (s Status) Next() []Status {
+	return transitions[s]
+}
+
+// Final reports whether order in status s never changes anymore.
+func (s Status) Final() bool {
+	return len(transitions[s]) == 0
+}
+
+func CanTransition(from, to Status) bool {
+	return slices.Contains(transitions[from], to)
+}
+
+// Event is a single entry of order status history.
+type Event struct {
+	Status    Status    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Validate checks that history starts with creation of the order, follows
+// allowed transitions and goes forward in time.
+func Validate(history []Event) error {
+	if len(history) == 0 || history[0].Status != Created {
+		return fmt.Errorf("history must start with %q", Created)
+	}
+	for i := 1; i < len(history); i++ {
+		prev, cur := history[i-1], history[i]
+		if !CanTransition(prev.Status, cur.Status) {
+			return fmt.Errorf("%w: %q -> %q", ErrInvalidTransition, prev.Status, cur.Status)
+		}
+		if cur.Timestamp.Before(prev.Timestamp) {
+			return fmt.Errorf("%q at %s precedes %q at %s",
+				cur.Status, cur.Timestamp.Format(time.DateTime), prev.Status, prev.Timestamp.Format(time.DateTime))
+		}
+	}
+	return nil
+}
+
+// Transition moves order to status to at given time, recording it in order
+// status history. Order row is locked, so concurrent transitions of the same
+// order are applied one after another and each of them is validated.
+func Transition(ctx context.Context, pg *pgxpool.Pool, orderID int32, to Status, at time.Time) error {
+	return pgx.BeginFunc(ctx, pg, func(tx pgx.Tx) error {
+		q := queries.New(tx)
+		current, err := q.SelectOrderStatusForUpdate(ctx, orderID)
+		if err != nil {
+			return fmt.Errorf("select status of order %d: %w", orderID, err)
+		}
+		if !CanTransition(Status(current), to) {
+			return fmt.Errorf("%w: %q -> %q", ErrInvalidTransition, current, to)
+		}
+
+		history, err := History(ctx, q, orderID)
+		if err != nil {
+			return err
+		}
+		// History keeps timestamps without time zone, which are read back
+		// as UTC, so they are compared with wall clock of at.
+		wall := time.Date(at.Year(), at.Month(), at.Day(), at.Hour(), at.Minute(), at.Second(), at.Nanosecond(), time.UTC)
+		if n := len(history); n > 0 && wall.Before(history[n-1].Timestamp) {
+			last := history[n-1]
+			return fmt.Errorf("%w: %q at %s precedes %q at %s", ErrBackdated,
+				to, at.Format(time.DateTime), last.Status, last.Timestamp.Format(time.DateTime))
+		}
+
+		if err := q.UpdateOrderStatus(ctx, queries.UpdateOrderStatusParams{
+			Status: string(to),
+			ID:     orderID,
+		}); err != nil {
+			return fmt.Errorf("update status of order %d: %w", orderID, err)
+		}
+		if err := q.CreateOrderStatus(ctx, queries.CreateOrderStatusParams{
+			OrderID:   orderID,
+			Status:    string(to),
+			Timestamp: pgtype.Timestamp{Time: at, Valid: true},
+		}); err != nil {
+			return fmt.Errorf("record status of order %d: %w", orderID, err)
+		}
+		return nil
+	})
+}
+
+// History returns status history of order ordered by time.
+func History(ctx context.Context, q *queries.Queries, orderID int32) ([]Event, error) {
+	rows, err := q.SelectOrderStatusHistory(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("select status history of order %d: %w", orderID, err)
+	}
+	history := make([]Event, len(rows))
+	for i, r := range rows {
+		history[i] = Event{Status: Status(r.Status), Timestamp: r.Timestamp.Time}
+	}
+	return history, nil
+}
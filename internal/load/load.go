@@ -0,0 +1,222 @@
+// Package load simulates OLTP traffic of delivery service: virtual users run
+// a weighted mix of short transactions against the database concurrently.
+package load
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+	"github.com/LeKSuS-04/mephi-db/internal/gen"
+)
+
+type Kind string
+
+const (
+	// BrowseMenu reads supplier with its dishes and commodities.
+	BrowseMenu Kind = "browse_menu"
+	// PlaceOrder creates payment, order with composition and its first
+	// status in a single transaction.
+	PlaceOrder Kind = "place_order"
+	// UpdateStatus moves order forward in its lifecycle, mostly one of
+	// orders placed during the run.
+	UpdateStatus Kind = "update_status"
+	// CourierLookup reads courier with orders they currently deliver.
+	CourierLookup Kind = "courier_lookup"
+	// SearchIngredient looks for dishes containing ingredient.
+	SearchIngredient Kind = "search_ingredient"
+)
+
+func Kinds() []Kind {
+	return []Kind{BrowseMenu, PlaceOrder, UpdateStatus, CourierLookup, SearchIngredient}
+}
+
+// Mix is relative weights of transaction kinds.
+type Mix map[Kind]int
+
+func DefaultMix() Mix {
+	return Mix{
+		BrowseMenu:       40,
+		PlaceOrder:       15,
+		UpdateStatus:     20,
+		CourierLookup:    15,
+		SearchIngredient: 10,
+	}
+}
+
+// ParseMix parses weights in kind=weight form. Kinds which are not
+// mentioned are not run.
+func ParseMix(specs []string) (Mix, error) {
+	mix := make(Mix, len(specs))
+	for _, spec := range specs {
+		name, value, ok := strings.Cut(spec, "=")
+		if !ok {
+			return nil, fmt.Errorf("transaction weight %q is not in kind=weight form", spec)
+		}
+		kind := Kind(name)
+		if !slices.Contains(Kinds(), kind) {
+			return nil, fmt.Errorf("unknown transaction kind %q", name)
+		}
+		weight, err := strconv.Atoi(value)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("weight of %q must be non-negative integer, got %q", name, value)
+		}
+		mix[kind] = weight
+	}
+	return mix, nil
+}
+
+// pick chooses kind with probability proportional to its weight.
+func (m Mix) pick(kinds []Kind, total int) Kind {
+	n := rand.IntN(total)
+	for _, kind := range kinds {
+		if n < m[kind] {
+			return kind
+		}
+		n -= m[kind]
+	}
+	panic("unreachable")
+}
+
+type Options struct {
+	Users    int
+	Duration time.Duration
+	Mix      Mix
+	// Think is pause of every virtual user between its transactions.
+	Think time.Duration
+}
+
+var transactions = map[Kind]func(ctx context.Context, pg *pgxpool.Pool, d *dataset) error{
+	BrowseMenu:       browseMenu,
+	PlaceOrder:       placeOrder,
+	UpdateStatus:     updateStatus,
+	CourierLookup:    courierLookup,
+	SearchIngredient: searchIngredient,
+}
+
+// Run loads ids of existing rows and runs transactions from opts.Users
+// concurrent virtual users until opts.Duration passes or ctx is done.
+func Run(ctx context.Context, pg *pgxpool.Pool, opts Options) (*Result, error) {
+	var kinds []Kind
+	var total int
+	for _, kind := range Kinds() {
+		if opts.Mix[kind] > 0 {
+			kinds = append(kinds, kind)
+			total += opts.Mix[kind]
+		}
+	}
+	if total == 0 {
+		return nil, errors.New("transaction mix is empty")
+	}
+	if opts.Users <= 0 {
+		return nil, fmt.Errorf("amount of virtual users must be positive, got %d", opts.Users)
+	}
+
+	log.Print("Loading dataset")
+	d, err := loadDataset(ctx, queries.New(pg))
+	if err != nil {
+		return nil, fmt.Errorf("load dataset: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Duration)
+	defer cancel()
+
+	log.Printf("Running %d virtual users for %s", opts.Users, opts.Duration)
+	recorders := make([]recorder, opts.Users)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := range recorders {
+		recorders[i] = make(recorder)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				kind := opts.Mix.pick(kinds, total)
+				txStart := time.Now()
+				err := transactions[kind](ctx, pg, d)
+				elapsed := time.Since(txStart)
+				if err != nil && ctx.Err() != nil {
+					// Transaction interrupted by the end of the run.
+					return
+				}
+				recorders[i].record(kind, elapsed, err)
+
+				if opts.Think > 0 {
+					select {
+					case <-time.After(opts.Think):
+					case <-ctx.Done():
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return summarize(kinds, recorders, time.Since(start), opts.Users), nil
+}
+
+// dataset keeps ids of rows transactions refer to.
+type dataset struct {
+	userIDs     []int32
+	cards       []queries.SelectUserCardOwnersRow
+	supplierIDs []int32
+	courierIDs  []int32
+	orderIDs    []int32
+	dishes      []queries.SelectDishCostsRow
+	commodities []queries.SelectCommodityCostsRow
+	ingredients []string
+
+	mu sync.Mutex
+	// placed are orders placed during the run which may still change
+	// their status.
+	placed []int32
+}
+
+func loadDataset(ctx context.Context, q *queries.Queries) (*dataset, error) {
+	d := &dataset{ingredients: gen.Ingredients()}
+	var err error
+	if d.userIDs, err = q.SelectUserIDs(ctx); err != nil {
+		return nil, fmt.Errorf("select user ids: %w", err)
+	}
+	if d.cards, err = q.SelectUserCardOwners(ctx); err != nil {
+		return nil, fmt.Errorf("select card owners: %w", err)
+	}
+	if d.supplierIDs, err = q.SelectSupplierIDs(ctx); err != nil {
+		return nil, fmt.Errorf("select supplier ids: %w", err)
+	}
+	if d.courierIDs, err = q.SelectCourierIDs(ctx); err != nil {
+		return nil, fmt.Errorf("select courier ids: %w", err)
+	}
+	if d.orderIDs, err = q.SelectOrderIDs(ctx); err != nil {
+		return nil, fmt.Errorf("select order ids: %w", err)
+	}
+	if d.dishes, err = q.SelectDishCosts(ctx); err != nil {
+		return nil, fmt.Errorf("select dish costs: %w", err)
+	}
+	if d.commodities, err = q.SelectCommodityCosts(ctx); err != nil {
+		return nil, fmt.Errorf("select commodity costs: %w", err)
+	}
+
+	for name, n := range map[string]int{
+		"users":     len(d.userIDs),
+		"suppliers": len(d.supplierIDs),
+		"couriers":  len(d.courierIDs),
+		"orders":    len(d.orderIDs),
+		"items":     len(d.dishes) + len(d.commodities),
+	} {
+		if n == 0 {
+			return nil, fmt.Errorf("database has no %s, generate dataset first", name)
+		}
+	}
+	return d, nil
+}
@@ -0,0 +1,125 @@
+package load
+
+import (
+	"errors"
+	"time"
+
+	"github.com/LeKSuS-04/mephi-db/internal/bench"
+	"github.com/LeKSuS-04/mephi-db/internal/lifecycle"
+)
+
+// bounds are upper bounds of latency histogram buckets in milliseconds.
+var bounds = []float64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000}
+
+type Result struct {
+	Users   int     `json:"users"`
+	Elapsed float64 `json:"elapsed_seconds"`
+	Stats   []Stats `json:"stats"`
+}
+
+// Stats describe transactions of a single kind. Latency and histogram
+// include successful transactions only, since the rest usually end early.
+type Stats struct {
+	Kind Kind `json:"kind"`
+	// Count is amount of all transactions, whatever their outcome.
+	Count int64 `json:"count"`
+	// Skipped transactions found nothing to do.
+	Skipped int64 `json:"skipped"`
+	// Conflicts are transactions which lost a race with concurrent ones,
+	// e.g. status updates of an order whose status has just changed. They
+	// are expected under load and are not errors.
+	Conflicts  int64         `json:"conflicts"`
+	Errors     int64         `json:"errors"`
+	ErrorRate  float64       `json:"error_rate"`
+	Throughput float64       `json:"throughput"`
+	Latency    bench.Latency `json:"latency"`
+	Histogram  []Bucket      `json:"histogram"`
+	// Error is the message of one of failures.
+	Error string `json:"error,omitempty"`
+}
+
+// Bucket counts transactions which took at most Le milliseconds and more
+// than bound of the previous bucket. The last bucket has zero Le and counts
+// transactions slower than every bound.
+type Bucket struct {
+	Le    float64 `json:"le_ms,omitempty"`
+	Count int64   `json:"count"`
+}
+
+type outcomes struct {
+	durations []time.Duration
+	skipped   int64
+	conflicts int64
+	errors    int64
+	err       error
+}
+
+// recorder collects outcomes of a single virtual user, so that users don't
+// contend with each other.
+type recorder map[Kind]*outcomes
+
+func (r recorder) record(kind Kind, d time.Duration, err error) {
+	o, ok := r[kind]
+	if !ok {
+		o = &outcomes{}
+		r[kind] = o
+	}
+	switch {
+	case err == nil:
+		o.durations = append(o.durations, d)
+	case errors.Is(err, errSkipped):
+		o.skipped++
+	case errors.Is(err, lifecycle.ErrInvalidTransition), errors.Is(err, lifecycle.ErrBackdated):
+		o.conflicts++
+	default:
+		o.errors++
+		o.err = err
+	}
+}
+
+func summarize(kinds []Kind, recorders []recorder, elapsed time.Duration, users int) *Result {
+	result := &Result{Users: users, Elapsed: elapsed.Seconds()}
+	for _, kind := range kinds {
+		s := Stats{Kind: kind}
+		var durations []time.Duration
+		for _, r := range recorders {
+			o, ok := r[kind]
+			if !ok {
+				continue
+			}
+			durations = append(durations, o.durations...)
+			s.Skipped += o.skipped
+			s.Conflicts += o.conflicts
+			s.Errors += o.errors
+			if o.err != nil {
+				s.Error = o.err.Error()
+			}
+		}
+
+		s.Count = int64(len(durations)) + s.Skipped + s.Conflicts + s.Errors
+		if s.Count > 0 {
+			s.ErrorRate = float64(s.Errors) / float64(s.Count)
+		}
+		s.Throughput = float64(s.Count) / elapsed.Seconds()
+		s.Latency = bench.Summarize(durations)
+		s.Histogram = histogram(durations)
+		result.Stats = append(result.Stats, s)
+	}
+	return result
+}
+
+func histogram(durations []time.Duration) []Bucket {
+	buckets := make([]Bucket, len(bounds)+1)
+	for i, le := range bounds {
+		buckets[i].Le = le
+	}
+	for _, d := range durations {
+		ms := float64(d) / float64(time.Millisecond)
+		i := 0
+		for i < len(bounds) && ms > bounds[i] {
+			i++
+		}
+		buckets[i].Count++
+	}
+	return buckets
+}
@@ -0,0 +1,210 @@
+package load
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+	"github.com/LeKSuS-04/mephi-db/internal/gen"
+	"github.com/LeKSuS-04/mephi-db/internal/lifecycle"
+)
+
+const (
+	maxPlacedOrders  = 10_000
+	searchResults    = 20
+	statusCandidates = 5
+)
+
+// errSkipped is returned by transactions which found nothing to do, e.g.
+// when none of sampled orders can change status anymore.
+var errSkipped = errors.New("nothing to do")
+
+func browseMenu(ctx context.Context, pg *pgxpool.Pool, d *dataset) error {
+	q := queries.New(pg)
+	supplierID := choose(d.supplierIDs)
+	if _, err := q.SelectSupplier(ctx, supplierID); err != nil {
+		return fmt.Errorf("select supplier %d: %w", supplierID, err)
+	}
+	if _, err := q.SelectSupplierDishes(ctx, supplierID); err != nil {
+		return fmt.Errorf("select dishes of supplier %d: %w", supplierID, err)
+	}
+	if _, err := q.SelectSupplierCommodities(ctx, supplierID); err != nil {
+		return fmt.Errorf("select commodities of supplier %d: %w", supplierID, err)
+	}
+	return nil
+}
+
+func placeOrder(ctx context.Context, pg *pgxpool.Pool, d *dataset) error {
+	var orderID int32
+	err := pgx.BeginFunc(ctx, pg, func(tx pgx.Tx) error {
+		q := queries.New(tx)
+		now := pgtype.Timestamp{Time: time.Now(), Valid: true}
+
+		userID := choose(d.userIDs)
+		payment := queries.CreatePaymentParams{
+			Method: choose([]string{"cash", "card", "online", "online"}),
+			Status: "successful",
+		}
+		if payment.Method != "cash" && len(d.cards) > 0 {
+			// Only the owner pays with a card.
+			card := choose(d.cards)
+			userID = card.UserID
+			payment.CardID = pgtype.Int4{Int32: card.ID, Valid: true}
+		}
+		paymentID, err := q.CreatePayment(ctx, payment)
+		if err != nil {
+			return fmt.Errorf("create payment: %w", err)
+		}
+
+		orderID, err = q.CreateOrder(ctx, queries.CreateOrderParams{
+			UserID:        userID,
+			Timestamp:     now,
+			SourceAddress: pgtype.Text{String: gofakeit.Address().Address, Valid: true},
+			TargetAddress: pgtype.Text{String: gofakeit.Address().Address, Valid: true},
+			CourierID:     pgtype.Int4{Int32: choose(d.courierIDs), Valid: true},
+			Status:        string(lifecycle.Created),
+			PaymentID:     pgtype.Int4{Int32: paymentID, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("create order: %w", err)
+		}
+
+		if _, err := q.AssignOrdersCommoditiesAndDishes(ctx, randomItems(d, orderID)); err != nil {
+			return fmt.Errorf("create composition of order %d: %w", orderID, err)
+		}
+		if err := q.CreateOrderStatus(ctx, queries.CreateOrderStatusParams{
+			OrderID:   orderID,
+			Status:    string(lifecycle.Created),
+			Timestamp: now,
+		}); err != nil {
+			return fmt.Errorf("record status of order %d: %w", orderID, err)
+		}
+		if err := q.FillOrderAmount(ctx, orderID); err != nil {
+			return fmt.Errorf("fill amounts of order %d: %w", orderID, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.placed) >= maxPlacedOrders {
+		d.placed = d.placed[1:]
+	}
+	d.placed = append(d.placed, orderID)
+	return nil
+}
+
+// randomItems returns distinct dishes and commodities priced at their
+// current cost, as many as generator puts into orders at most.
+func randomItems(d *dataset, orderID int32) []queries.AssignOrdersCommoditiesAndDishesParams {
+	count := rand.IntN(min(gen.MaxItemsPerOrder, len(d.dishes)+len(d.commodities))) + 1
+	chosen := make(map[int]struct{}, count)
+	items := make([]queries.AssignOrdersCommoditiesAndDishesParams, 0, count)
+	for len(items) < count {
+		idx := rand.IntN(len(d.dishes) + len(d.commodities))
+		if _, ok := chosen[idx]; ok {
+			continue
+		}
+		chosen[idx] = struct{}{}
+
+		item := queries.AssignOrdersCommoditiesAndDishesParams{
+			OrderID:  orderID,
+			Quantity: int32(rand.IntN(gen.MaxItemQuantity) + 1),
+		}
+		if idx < len(d.dishes) {
+			item.DishID = pgtype.Int4{Int32: d.dishes[idx].ID, Valid: true}
+			item.UnitPrice = d.dishes[idx].Cost
+		} else {
+			commodity := d.commodities[idx-len(d.dishes)]
+			item.CommodityID = pgtype.Int4{Int32: commodity.ID, Valid: true}
+			item.UnitPrice = commodity.Cost
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// updateStatus moves order in progress to the next status, which is
+// occasionally cancellation or refund. Orders placed during the run are
+// preferred, since most of generated ones are already finished. If none of
+// sampled orders can change status, errSkipped is returned.
+func updateStatus(ctx context.Context, pg *pgxpool.Pool, d *dataset) error {
+	q := queries.New(pg)
+	for range statusCandidates {
+		orderID, placed := d.orderToUpdate()
+		current, err := q.SelectOrderStatus(ctx, orderID)
+		if err != nil {
+			return fmt.Errorf("select status of order %d: %w", orderID, err)
+		}
+
+		status := lifecycle.Status(current)
+		if status.Final() || status == lifecycle.Delivered {
+			if placed {
+				d.forget(orderID)
+			}
+			continue
+		}
+
+		next := status.Next()
+		to := next[0]
+		if rand.IntN(20) == 0 {
+			to = next[len(next)-1]
+		}
+		return lifecycle.Transition(ctx, pg, orderID, to, time.Now())
+	}
+	return errSkipped
+}
+
+func (d *dataset) orderToUpdate() (int32, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.placed) > 0 && rand.IntN(5) != 0 {
+		return choose(d.placed), true
+	}
+	return choose(d.orderIDs), false
+}
+
+func (d *dataset) forget(orderID int32) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.placed = slices.DeleteFunc(d.placed, func(id int32) bool { return id == orderID })
+}
+
+func courierLookup(ctx context.Context, pg *pgxpool.Pool, d *dataset) error {
+	q := queries.New(pg)
+	courierID := choose(d.courierIDs)
+	if _, err := q.SelectCourier(ctx, courierID); err != nil {
+		return fmt.Errorf("select courier %d: %w", courierID, err)
+	}
+	if _, err := q.SelectCourierActiveOrders(ctx, pgtype.Int4{Int32: courierID, Valid: true}); err != nil {
+		return fmt.Errorf("select active orders of courier %d: %w", courierID, err)
+	}
+	return nil
+}
+
+func searchIngredient(ctx context.Context, pg *pgxpool.Pool, d *dataset) error {
+	ingredient := choose(d.ingredients)
+	if _, err := queries.New(pg).SearchDishesByIngredient(ctx, queries.SearchDishesByIngredientParams{
+		Pattern:    pgtype.Text{String: "%" + ingredient + "%", Valid: true},
+		MaxResults: searchResults,
+	}); err != nil {
+		return fmt.Errorf("search dishes by %q: %w", ingredient, err)
+	}
+	return nil
+}
+
+func choose[T any](values []T) T {
+	return values[rand.IntN(len(values))]
+}
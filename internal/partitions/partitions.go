@@ -0,0 +1,133 @@
+package partitions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Partitioning itself is enabled by partition_by_month optional migration,
+// which also creates create_month_partition function used here.
+var tableNames = []string{
+	"payments",
+	"orders",
+}
+
+var ErrNotEnabled = errors.New("partitioning is not enabled")
+
+type Partition struct {
+	Table string
+	Name  string
+	From  time.Time
+	To    time.Time
+}
+
+// Enabled reports whether orders table is partitioned.
+func Enabled(ctx context.Context, pg *pgxpool.Pool) (bool, error) {
+	var partitioned bool
+	err := pg.QueryRow(ctx, "SELECT relkind = 'p' FROM pg_class WHERE oid = 'orders'::regclass").Scan(&partitioned)
+	if err != nil {
+		return false, fmt.Errorf("check orders relkind: %w", err)
+	}
+	return partitioned, nil
+}
+
+// EnsureRange creates missing partitions for every month between from and to,
+// both ends inclusive.
+func EnsureRange(ctx context.Context, pg *pgxpool.Pool, from, to time.Time) error {
+	enabled, err := Enabled(ctx, pg)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return ErrNotEnabled
+	}
+
+	for _, table := range tableNames {
+		for month := monthStart(from); !month.After(to); month = month.AddDate(0, 1, 0) {
+			var name string
+			err := pg.QueryRow(ctx, "SELECT create_month_partition($1, $2)", table, month).Scan(&name)
+			if err != nil {
+				return fmt.Errorf("create partition of %q for %s: %w", table, month.Format("2006-01"), err)
+			}
+			log.Printf("Ensured partition %q", name)
+		}
+	}
+	return nil
+}
+
+// Ensure creates partitions for current month and ahead months after it.
+func Ensure(ctx context.Context, pg *pgxpool.Pool, ahead int) error {
+	now := time.Now()
+	return EnsureRange(ctx, pg, now, monthStart(now).AddDate(0, ahead, 0))
+}
+
+// List returns all month partitions of partitioned tables ordered by table
+// and range.
+func List(ctx context.Context, pg *pgxpool.Pool) ([]Partition, error) {
+	rows, err := pg.Query(ctx, `
+		SELECT parent.relname, child.relname
+		FROM pg_inherits
+		JOIN pg_class parent ON parent.oid = pg_inherits.inhparent
+		JOIN pg_class child ON child.oid = pg_inherits.inhrelid
+		WHERE parent.relname = ANY($1)
+		ORDER BY parent.relname, child.relname`,
+		tableNames,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("select partitions: %w", err)
+	}
+	defer rows.Close()
+
+	var partitions []Partition
+	for rows.Next() {
+		var p Partition
+		if err := rows.Scan(&p.Table, &p.Name); err != nil {
+			return nil, fmt.Errorf("scan partition: %w", err)
+		}
+		month, err := time.Parse("200601", strings.TrimPrefix(p.Name, p.Table+"_p"))
+		if err != nil {
+			log.Printf("Skipping partition %q with unknown naming", p.Name)
+			continue
+		}
+		p.From = month
+		p.To = month.AddDate(0, 1, 0)
+		partitions = append(partitions, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("select partitions: %w", err)
+	}
+	return partitions, nil
+}
+
+// Drop removes partitions whose whole range lies before the given time and
+// returns names of dropped partitions.
+func Drop(ctx context.Context, pg *pgxpool.Pool, before time.Time) ([]string, error) {
+	partitions, err := List(ctx, pg)
+	if err != nil {
+		return nil, err
+	}
+
+	var dropped []string
+	for _, p := range partitions {
+		if p.To.After(before) {
+			continue
+		}
+		if _, err := pg.Exec(ctx, "DROP TABLE "+pgx.Identifier{p.Name}.Sanitize()); err != nil {
+			return dropped, fmt.Errorf("drop partition %q: %w", p.Name, err)
+		}
+		log.Printf("Dropped partition %q", p.Name)
+		dropped = append(dropped, p.Name)
+	}
+	return dropped, nil
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
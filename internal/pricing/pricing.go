@@ -0,0 +1,176 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+)
+
+// Target is either a dish or a commodity, exactly one of ids is valid.
+type Target struct {
+	DishID      pgtype.Int4 `json:"dish_id"`
+	CommodityID pgtype.Int4 `json:"commodity_id"`
+}
+
+// Discount is an active discount which may apply to an order. Discount
+// applies to its targets, or to every position of the order when it is the
+// discount attached to the order and has no targets.
+type Discount struct {
+	ID         int32    `json:"id"`
+	Terms      Terms    `json:"terms"`
+	Targets    []Target `json:"targets"`
+	WholeOrder bool     `json:"whole_order"`
+}
+
+func (d Discount) appliesTo(t Target) bool {
+	return d.WholeOrder || slices.Contains(d.Targets, t)
+}
+
+// Line is a single position of order composition. Cost is the unit price
+// recorded when the order was created.
+type Line struct {
+	PositionID int32  `json:"position_id"`
+	Target     Target `json:"target"`
+	Cost       int64  `json:"cost"`
+	Quantity   int32  `json:"quantity"`
+}
+
+type LinePrice struct {
+	Line
+	Reduction  int64       `json:"reduction"`
+	Price      int64       `json:"price"`
+	DiscountID pgtype.Int4 `json:"discount_id"`
+}
+
+type Price struct {
+	Lines    []LinePrice `json:"lines"`
+	Subtotal int64       `json:"subtotal"`
+	Discount int64       `json:"discount"`
+	Total    int64       `json:"total"`
+}
+
+// item groups units of the same target bought at the same price.
+type item struct {
+	target Target
+	cost   int64
+}
+
+// Compute applies discounts to order lines. Discounts don't stack: units of
+// the same item get the single discount reducing their sum the most.
+// The same rules are implemented by order_discount_amount SQL function.
+func Compute(lines []Line, discounts []Discount) Price {
+	var p Price
+	p.Lines = make([]LinePrice, len(lines))
+	groups := make(map[item][]int)
+	var order []item
+	for i, l := range lines {
+		amount := l.Cost * int64(l.Quantity)
+		p.Lines[i] = LinePrice{Line: l, Price: amount}
+		p.Subtotal += amount
+		it := item{target: l.Target, cost: l.Cost}
+		if _, ok := groups[it]; !ok {
+			order = append(order, it)
+		}
+		groups[it] = append(groups[it], i)
+	}
+
+	for _, it := range order {
+		idxs := groups[it]
+		var units int
+		for _, i := range idxs {
+			units += int(lines[i].Quantity)
+		}
+
+		var best []int64
+		var bestSum int64
+		var bestID pgtype.Int4
+		for _, d := range discounts {
+			if !d.appliesTo(it.target) {
+				continue
+			}
+			reductions := d.Terms.reductions(it.cost, units)
+			var sum int64
+			for _, r := range reductions {
+				sum += r
+			}
+			if sum > bestSum {
+				best, bestSum, bestID = reductions, sum, pgtype.Int4{Int32: d.ID, Valid: true}
+			}
+		}
+		if best == nil {
+			continue
+		}
+
+		for _, i := range idxs {
+			var reduction int64
+			for _, r := range best[:lines[i].Quantity] {
+				reduction += r
+			}
+			best = best[lines[i].Quantity:]
+			p.Lines[i].Reduction = reduction
+			p.Lines[i].Price -= reduction
+			p.Lines[i].DiscountID = bestID
+		}
+		p.Discount += bestSum
+	}
+	p.Total = p.Subtotal - p.Discount
+	return p
+}
+
+// OrderPrice loads composition of order and discounts applicable to it and
+// computes its price.
+func OrderPrice(ctx context.Context, q *queries.Queries, orderID int32) (*Price, error) {
+	rows, err := q.SelectOrderLines(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("select order lines: %w", err)
+	}
+	lines := make([]Line, len(rows))
+	for i, r := range rows {
+		lines[i] = Line{
+			PositionID: r.ID,
+			Target:     Target{DishID: r.DishID, CommodityID: r.CommodityID},
+			Cost:       r.UnitPrice,
+			Quantity:   r.Quantity,
+		}
+	}
+
+	discounts, err := applicableDiscounts(ctx, q, orderID)
+	if err != nil {
+		return nil, err
+	}
+	p := Compute(lines, discounts)
+	return &p, nil
+}
+
+func applicableDiscounts(ctx context.Context, q *queries.Queries, orderID int32) ([]Discount, error) {
+	rows, err := q.SelectApplicableDiscounts(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("select applicable discounts: %w", err)
+	}
+
+	var discounts []Discount
+	byID := make(map[int32]int)
+	for _, r := range rows {
+		idx, ok := byID[r.ID]
+		if !ok {
+			terms, err := ParseTerms(r.Type, r.Terms)
+			if err != nil {
+				return nil, fmt.Errorf("parse terms of discount %d: %w", r.ID, err)
+			}
+			idx = len(discounts)
+			byID[r.ID] = idx
+			discounts = append(discounts, Discount{ID: r.ID, Terms: terms})
+		}
+		target := Target{DishID: r.DishID, CommodityID: r.CommodityID}
+		if target.DishID.Valid || target.CommodityID.Valid {
+			discounts[idx].Targets = append(discounts[idx].Targets, target)
+		} else {
+			discounts[idx].WholeOrder = true
+		}
+	}
+	return discounts, nil
+}
@@ -0,0 +1,193 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+	"github.com/LeKSuS-04/mephi-db/internal/fixtures"
+	"github.com/LeKSuS-04/mephi-db/internal/tempdb/tempdbtest"
+)
+
+func dish(id int32) Target {
+	return Target{DishID: pgtype.Int4{Int32: id, Valid: true}}
+}
+
+func commodity(id int32) Target {
+	return Target{CommodityID: pgtype.Int4{Int32: id, Valid: true}}
+}
+
+func TestCompute(t *testing.T) {
+	tenPercent := Discount{ID: 1, Terms: Terms{Type: Percentage, Percentage: 10}, Targets: []Target{dish(1)}}
+	fiveOff := Discount{ID: 2, Terms: Terms{Type: Const, Value: 50}, Targets: []Target{dish(1)}}
+	twoPlusOne := Discount{ID: 3, Terms: Terms{Type: ExtraForFree, MustBy: 2, GetForFree: 1}, Targets: []Target{dish(1)}}
+
+	tests := []struct {
+		name       string
+		lines      []Line
+		discounts  []Discount
+		reductions []int64
+		discountID []int32
+	}{
+		{
+			name:       "without discounts",
+			lines:      []Line{{Target: dish(1), Cost: 1000, Quantity: 2}},
+			reductions: []int64{0},
+			discountID: []int32{0},
+		},
+		{
+			name:       "percentage of every unit",
+			lines:      []Line{{Target: dish(1), Cost: 1000, Quantity: 2}, {Target: dish(2), Cost: 1000, Quantity: 1}},
+			discounts:  []Discount{tenPercent},
+			reductions: []int64{200, 0},
+			discountID: []int32{1, 0},
+		},
+		{
+			name:       "full percentage makes item free",
+			lines:      []Line{{Target: dish(1), Cost: 999, Quantity: 3}},
+			discounts:  []Discount{{ID: 1, Terms: Terms{Type: Percentage, Percentage: 100}, Targets: []Target{dish(1)}}},
+			reductions: []int64{2997},
+			discountID: []int32{1},
+		},
+		{
+			name:       "const capped by cost",
+			lines:      []Line{{Target: dish(1), Cost: 30, Quantity: 2}},
+			discounts:  []Discount{fiveOff},
+			reductions: []int64{60},
+			discountID: []int32{2},
+		},
+		{
+			name:       "discounts don't stack, the best one applies",
+			lines:      []Line{{Target: dish(1), Cost: 1000, Quantity: 1}},
+			discounts:  []Discount{fiveOff, tenPercent},
+			reductions: []int64{100},
+			discountID: []int32{1},
+		},
+		{
+			name:       "best discount depends on cost",
+			lines:      []Line{{Target: dish(1), Cost: 300, Quantity: 1}},
+			discounts:  []Discount{tenPercent, fiveOff},
+			reductions: []int64{50},
+			discountID: []int32{2},
+		},
+		{
+			name:       "extra for free makes last units free",
+			lines:      []Line{{Target: dish(1), Cost: 100, Quantity: 2}, {Target: dish(1), Cost: 100, Quantity: 2}},
+			discounts:  []Discount{twoPlusOne},
+			reductions: []int64{0, 100},
+			discountID: []int32{3, 3},
+		},
+		{
+			name:       "extra for free counts incomplete tail",
+			lines:      []Line{{Target: dish(1), Cost: 100, Quantity: 3}},
+			discounts:  []Discount{{ID: 4, Terms: Terms{Type: ExtraForFree, MustBy: 2, GetForFree: 2}, Targets: []Target{dish(1)}}},
+			reductions: []int64{100},
+			discountID: []int32{4},
+		},
+		{
+			name:       "units at different prices are different items",
+			lines:      []Line{{Target: dish(1), Cost: 100, Quantity: 2}, {Target: dish(1), Cost: 200, Quantity: 1}},
+			discounts:  []Discount{twoPlusOne},
+			reductions: []int64{0, 0},
+			discountID: []int32{0, 0},
+		},
+		{
+			name:       "whole order discount applies to every position",
+			lines:      []Line{{Target: dish(1), Cost: 1000, Quantity: 1}, {Target: commodity(1), Cost: 500, Quantity: 2}},
+			discounts:  []Discount{{ID: 5, Terms: Terms{Type: Percentage, Percentage: 20}, WholeOrder: true}},
+			reductions: []int64{200, 200},
+			discountID: []int32{5, 5},
+		},
+		{
+			name:       "dish and commodity with the same id differ",
+			lines:      []Line{{Target: commodity(1), Cost: 1000, Quantity: 1}},
+			discounts:  []Discount{tenPercent},
+			reductions: []int64{0},
+			discountID: []int32{0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Compute(tt.lines, tt.discounts)
+
+			var subtotal, discount int64
+			for i, l := range p.Lines {
+				amount := l.Cost * int64(l.Quantity)
+				subtotal += amount
+				discount += l.Reduction
+				if l.Reduction != tt.reductions[i] || l.Price != amount-l.Reduction {
+					t.Errorf("line %d: reduction %d and price %d, want reduction %d of %d", i, l.Reduction, l.Price, tt.reductions[i], amount)
+				}
+				if l.DiscountID.Int32 != tt.discountID[i] || l.DiscountID.Valid != (tt.discountID[i] != 0) {
+					t.Errorf("line %d: discount %+v, want %d", i, l.DiscountID, tt.discountID[i])
+				}
+			}
+			if p.Subtotal != subtotal || p.Discount != discount || p.Total != subtotal-discount {
+				t.Errorf("got subtotal %d, discount %d, total %d, want %d, %d, %d",
+					p.Subtotal, p.Discount, p.Total, subtotal, discount, subtotal-discount)
+			}
+		})
+	}
+}
+
+// TestComputeMatchesSQL checks that Compute and order_discount_amount SQL
+// function agree on discounts of every fixture order.
+func TestComputeMatchesSQL(t *testing.T) {
+	server := tempdbtest.Start(t, "../../sql/schema.sql")
+	ctx := context.Background()
+
+	fs, err := fixtures.All()
+	if err != nil {
+		t.Fatalf("load fixtures: %v", err)
+	}
+	for _, f := range fs {
+		t.Run(f.Name, func(t *testing.T) {
+			tx, err := server.Pool.Begin(ctx)
+			if err != nil {
+				t.Fatalf("begin: %v", err)
+			}
+			defer tx.Rollback(ctx)
+			if err := fixtures.Load(ctx, tx, f); err != nil {
+				t.Fatal(err)
+			}
+
+			mismatches, err := compareWithSQL(ctx, tx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, m := range mismatches {
+				t.Error(m)
+			}
+		})
+	}
+}
+
+func compareWithSQL(ctx context.Context, tx pgx.Tx) ([]string, error) {
+	q := queries.New(tx)
+	orderIDs, err := q.SelectOrderIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("select order ids: %w", err)
+	}
+	slices.Sort(orderIDs)
+
+	var mismatches []string
+	for _, id := range orderIDs {
+		p, err := OrderPrice(ctx, q, id)
+		if err != nil {
+			return nil, fmt.Errorf("price of order %d: %w", id, err)
+		}
+		var want int64
+		if err := tx.QueryRow(ctx, "SELECT order_discount_amount($1)", id).Scan(&want); err != nil {
+			return nil, fmt.Errorf("discount amount of order %d: %w", id, err)
+		}
+		if p.Discount != want {
+			mismatches = append(mismatches, fmt.Sprintf("order %d: Compute gives discount %d, SQL gives %d", id, p.Discount, want))
+		}
+	}
+	return mismatches, nil
+}
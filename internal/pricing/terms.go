@@ -0,0 +1,88 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type Type string
+
+const (
+	// Percentage reduces price of every unit by given percent.
+	Percentage Type = "percentage"
+	// Const reduces price of every unit by given value, but not below zero.
+	Const Type = "const"
+	// ExtraForFree makes GetForFree units free after every MustBy bought
+	// ones. Incomplete tail counts as well: buying 3 units with must_by 2
+	// and get_for_free 2 makes the third one free.
+	ExtraForFree Type = "extra_for_free"
+)
+
+// Terms are parsed discounts.terms, only fields of its Type are set.
+type Terms struct {
+	Type       Type  `json:"type"`
+	Percentage int64 `json:"percentage,omitempty"`
+	Value      int64 `json:"value,omitempty"`
+	MustBy     int64 `json:"must_by,omitempty"`
+	GetForFree int64 `json:"get_for_free,omitempty"`
+}
+
+// ParseTerms decodes terms JSON of discount of given type.
+func ParseTerms(typ string, raw []byte) (Terms, error) {
+	var fields struct {
+		Percentage *int64 `json:"percentage"`
+		Value      *int64 `json:"value"`
+		MustBy     *int64 `json:"must_by"`
+		GetForFree *int64 `json:"get_for_free"`
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return Terms{}, fmt.Errorf("decode terms: %w", err)
+	}
+
+	t := Terms{Type: Type(typ)}
+	switch t.Type {
+	case Percentage:
+		if fields.Percentage == nil || *fields.Percentage < 0 || *fields.Percentage > 100 {
+			return Terms{}, fmt.Errorf("percentage terms require percentage between 0 and 100")
+		}
+		t.Percentage = *fields.Percentage
+	case Const:
+		if fields.Value == nil || *fields.Value < 0 {
+			return Terms{}, fmt.Errorf("const terms require non-negative value")
+		}
+		t.Value = *fields.Value
+	case ExtraForFree:
+		if fields.MustBy == nil || fields.GetForFree == nil || *fields.MustBy < 1 || *fields.GetForFree < 1 {
+			return Terms{}, fmt.Errorf("extra_for_free terms require positive must_by and get_for_free")
+		}
+		t.MustBy = *fields.MustBy
+		t.GetForFree = *fields.GetForFree
+	default:
+		return Terms{}, fmt.Errorf("unknown discount type %q", typ)
+	}
+	return t, nil
+}
+
+// reductions returns how much cheaper each of units of the same item with
+// given cost gets. Free units of ExtraForFree are the last ones.
+func (t Terms) reductions(cost int64, units int) []int64 {
+	r := make([]int64, units)
+	switch t.Type {
+	case Percentage:
+		for i := range r {
+			r[i] = cost * t.Percentage / 100
+		}
+	case Const:
+		for i := range r {
+			r[i] = min(t.Value, cost)
+		}
+	case ExtraForFree:
+		n := int64(units)
+		group := t.MustBy + t.GetForFree
+		free := n/group*t.GetForFree + max(0, n%group-t.MustBy)
+		for i := n - free; i < n; i++ {
+			r[i] = cost
+		}
+	}
+	return r
+}
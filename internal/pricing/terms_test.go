@@ -0,0 +1,52 @@
+package pricing
+
+import (
+	"testing"
+)
+
+func TestParseTerms(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     string
+		raw     string
+		want    Terms
+		wantErr bool
+	}{
+		{name: "percentage", typ: "percentage", raw: `{"percentage": 15}`, want: Terms{Type: Percentage, Percentage: 15}},
+		{name: "full percentage", typ: "percentage", raw: `{"percentage": 100}`, want: Terms{Type: Percentage, Percentage: 100}},
+		{name: "const", typ: "const", raw: `{"value": 5000}`, want: Terms{Type: Const, Value: 5000}},
+		{name: "zero const", typ: "const", raw: `{"value": 0}`, want: Terms{Type: Const}},
+		{name: "extra for free", typ: "extra_for_free", raw: `{"must_by": 2, "get_for_free": 1}`, want: Terms{Type: ExtraForFree, MustBy: 2, GetForFree: 1}},
+		{name: "fields of other types ignored", typ: "const", raw: `{"value": 10, "percentage": 50}`, want: Terms{Type: Const, Value: 10}},
+
+		{name: "percentage above 100", typ: "percentage", raw: `{"percentage": 101}`, wantErr: true},
+		{name: "negative percentage", typ: "percentage", raw: `{"percentage": -1}`, wantErr: true},
+		{name: "missing percentage", typ: "percentage", raw: `{"value": 10}`, wantErr: true},
+		{name: "negative const", typ: "const", raw: `{"value": -10}`, wantErr: true},
+		{name: "missing value", typ: "const", raw: `{}`, wantErr: true},
+		{name: "zero must_by", typ: "extra_for_free", raw: `{"must_by": 0, "get_for_free": 1}`, wantErr: true},
+		{name: "missing get_for_free", typ: "extra_for_free", raw: `{"must_by": 2}`, wantErr: true},
+		{name: "fractional value", typ: "const", raw: `{"value": 10.5}`, wantErr: true},
+		{name: "string value", typ: "const", raw: `{"value": "10"}`, wantErr: true},
+		{name: "malformed JSON", typ: "const", raw: `{"value": `, wantErr: true},
+		{name: "not an object", typ: "const", raw: `[10]`, wantErr: true},
+		{name: "unknown type", typ: "bonus", raw: `{"value": 10}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTerms(tt.typ, []byte(tt.raw))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parsed %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Package replay reproduces captured query load against the database.
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Entry is a single statement of captured log, one JSON object per line:
+//
+//	{"timestamp": "2024-10-01T12:00:00.123Z", "name": "SelectSupplier",
+//	 "sql": "SELECT ... WHERE id = $1", "args": [42], "duration_ms": 0.8}
+//
+// Name is optional, statements without it are grouped by their SQL.
+type Entry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Name      string    `json:"name,omitempty"`
+	SQL       string    `json:"sql"`
+	Args      []any     `json:"args,omitempty"`
+	// Duration is the recorded latency in milliseconds, zero if unknown.
+	Duration float64 `json:"duration_ms,omitempty"`
+}
+
+// Key identifies statement in the report.
+func (e Entry) Key() string {
+	if e.Name != "" {
+		return e.Name
+	}
+	return strings.Join(strings.Fields(e.SQL), " ")
+}
+
+// Read parses log and returns entries ordered by timestamp. Integer
+// arguments are decoded as int64, so that they can be passed to integer
+// parameters.
+func Read(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		var e Entry
+		if err := dec.Decode(&e); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if e.SQL == "" {
+			return nil, fmt.Errorf("line %d: statement has no sql", line)
+		}
+		if e.Timestamp.IsZero() {
+			return nil, fmt.Errorf("line %d: statement has no timestamp", line)
+		}
+		for i, arg := range e.Args {
+			if n, ok := arg.(json.Number); ok {
+				e.Args[i] = number(n)
+			}
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(entries, func(a, b Entry) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+	return entries, nil
+}
+
+func number(n json.Number) any {
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+	return n.String()
+}
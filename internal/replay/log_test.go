@@ -0,0 +1,64 @@
+package replay
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRead(t *testing.T) {
+	log := `
+{"timestamp": "2024-10-01T12:00:02Z", "name": "B", "sql": "SELECT 2"}
+
+{"timestamp": "2024-10-01T12:00:00Z", "name": "A", "sql": "SELECT $1, $2, $3, $4, $5", "args": [42, -7, 0.5, "x", null], "duration_ms": 0.8}
+{"timestamp": "2024-10-01T12:00:02Z", "sql": "SELECT  3", "args": [1e3, 12345678901234567890]}
+`
+	entries, err := Read(strings.NewReader(log))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	at := time.Date(2024, time.October, 1, 12, 0, 0, 0, time.UTC)
+	want := []Entry{
+		{Timestamp: at, Name: "A", SQL: "SELECT $1, $2, $3, $4, $5", Args: []any{int64(42), int64(-7), 0.5, "x", nil}, Duration: 0.8},
+		// Entries with equal timestamps keep their order in log.
+		{Timestamp: at.Add(2 * time.Second), Name: "B", SQL: "SELECT 2"},
+		{Timestamp: at.Add(2 * time.Second), SQL: "SELECT  3", Args: []any{1000.0, 12345678901234567890.0}},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("got %#v, want %#v", entries, want)
+	}
+	if key := entries[2].Key(); key != "SELECT 3" {
+		t.Errorf("key of unnamed statement is %q, want SQL with collapsed spaces", key)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		log  string
+		want string
+	}{
+		{name: "malformed JSON", log: `{"timestamp": "2024-10-01T12:00:00Z", "sql": `, want: "line 1: "},
+		{name: "missing sql", log: `{"timestamp": "2024-10-01T12:00:00Z"}`, want: "line 1: statement has no sql"},
+		{name: "missing timestamp", log: `{"sql": "SELECT 1"}`, want: "line 1: statement has no timestamp"},
+		{name: "bad timestamp", log: `{"timestamp": "yesterday", "sql": "SELECT 1"}`, want: "line 1: "},
+		{
+			name: "line numbers count empty lines",
+			log:  "{\"timestamp\": \"2024-10-01T12:00:00Z\", \"sql\": \"SELECT 1\"}\n\n{\"sql\": \"SELECT 2\"}",
+			want: "line 3: statement has no timestamp",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := Read(strings.NewReader(tt.log))
+			if err == nil {
+				t.Fatalf("read %d entries, want error", len(entries))
+			}
+			if !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("got error %q, want it to start with %q", err, tt.want)
+			}
+		})
+	}
+}
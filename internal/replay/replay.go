@@ -0,0 +1,206 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/LeKSuS-04/mephi-db/internal/bench"
+)
+
+type Options struct {
+	// Fast replays statements as fast as possible from Workers concurrent
+	// connections instead of keeping recorded inter-arrival times.
+	Fast    bool
+	Workers int
+	// Scale speeds up replay with recorded timing, e.g. 2 sends statements
+	// twice as often as they were recorded.
+	Scale float64
+}
+
+type Result struct {
+	Statements int     `json:"statements"`
+	Elapsed    float64 `json:"elapsed_seconds"`
+	// Recorded is the time span of replayed log in seconds.
+	Recorded float64 `json:"recorded_seconds"`
+	// MaxLag is the longest delay in milliseconds of sending statement
+	// after its scheduled time. Large lag means database or pool could not
+	// keep up with recorded rate.
+	MaxLag  float64 `json:"max_lag_ms"`
+	Results []Stats `json:"results"`
+}
+
+// Stats compare recorded and replayed latency of a single statement.
+// Replayed latency includes successful executions only.
+type Stats struct {
+	Statement string        `json:"statement"`
+	Count     int           `json:"count"`
+	Errors    int           `json:"errors"`
+	Recorded  bench.Latency `json:"recorded"`
+	Replayed  bench.Latency `json:"replayed"`
+	// Change is relative difference of replayed median latency from the
+	// recorded one, zero if it was not recorded.
+	Change float64 `json:"change"`
+	Error  string  `json:"error,omitempty"`
+}
+
+type outcome struct {
+	duration time.Duration
+	lag      time.Duration
+	err      error
+}
+
+// Run replays entries, which must be ordered by timestamp, and compares
+// their latency with the recorded one. Statements are executed
+// concurrently, so ones depending on effects of each other may behave
+// differently than in the log.
+func Run(ctx context.Context, pg *pgxpool.Pool, entries []Entry, opts Options) (*Result, error) {
+	if len(entries) == 0 {
+		return nil, errors.New("log has no statements")
+	}
+	if !opts.Fast && opts.Scale <= 0 {
+		return nil, fmt.Errorf("scale must be positive, got %v", opts.Scale)
+	}
+	if opts.Fast && opts.Workers <= 0 {
+		return nil, fmt.Errorf("amount of workers must be positive, got %d", opts.Workers)
+	}
+
+	outcomes := make([]outcome, len(entries))
+	start := time.Now()
+	if opts.Fast {
+		log.Printf("Replaying %d statements with %d workers", len(entries), opts.Workers)
+		replayFast(ctx, pg, entries, outcomes, opts.Workers)
+	} else {
+		log.Printf("Replaying %d statements at %vx recorded rate", len(entries), opts.Scale)
+		replayTimed(ctx, pg, entries, outcomes, opts.Scale)
+	}
+	elapsed := time.Since(start)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return summarize(entries, outcomes, elapsed), nil
+}
+
+func replayFast(ctx context.Context, pg *pgxpool.Pool, entries []Entry, outcomes []outcome, workers int) {
+	idxs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range idxs {
+				outcomes[i].duration, outcomes[i].err = execute(ctx, pg, entries[i])
+			}
+		}()
+	}
+	for i := range entries {
+		select {
+		case idxs <- i:
+		case <-ctx.Done():
+		}
+	}
+	close(idxs)
+	wg.Wait()
+}
+
+// replayTimed sends every statement at its scheduled time, but runs no more
+// of them at once than pool has connections. Statements waiting for a free
+// slot are delayed, which shows up as lag.
+func replayTimed(ctx context.Context, pg *pgxpool.Pool, entries []Entry, outcomes []outcome, scale float64) {
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, pg.Config().MaxConns)
+	start := time.Now()
+	first := entries[0].Timestamp
+	for i, e := range entries {
+		at := start.Add(time.Duration(float64(e.Timestamp.Sub(first)) / scale))
+		timer := time.NewTimer(time.Until(at))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			wg.Wait()
+			return
+		}
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			outcomes[i].lag = time.Since(at)
+			outcomes[i].duration, outcomes[i].err = execute(ctx, pg, e)
+		}()
+	}
+	wg.Wait()
+}
+
+func execute(ctx context.Context, pg *pgxpool.Pool, e Entry) (time.Duration, error) {
+	start := time.Now()
+	rows, err := pg.Query(ctx, e.SQL, e.Args...)
+	if err != nil {
+		return 0, err
+	}
+	for rows.Next() {
+	}
+	rows.Close()
+	return time.Since(start), rows.Err()
+}
+
+func summarize(entries []Entry, outcomes []outcome, elapsed time.Duration) *Result {
+	result := &Result{
+		Statements: len(entries),
+		Elapsed:    elapsed.Seconds(),
+		Recorded:   entries[len(entries)-1].Timestamp.Sub(entries[0].Timestamp).Seconds(),
+	}
+
+	type group struct {
+		stats    Stats
+		recorded []time.Duration
+		replayed []time.Duration
+	}
+	var groups []*group
+	byKey := make(map[string]*group)
+	for i, e := range entries {
+		o := outcomes[i]
+		result.MaxLag = max(result.MaxLag, float64(o.lag)/float64(time.Millisecond))
+
+		key := e.Key()
+		g, ok := byKey[key]
+		if !ok {
+			g = &group{stats: Stats{Statement: key}}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.stats.Count++
+		if e.Duration > 0 {
+			g.recorded = append(g.recorded, time.Duration(e.Duration*float64(time.Millisecond)))
+		}
+		if o.err != nil {
+			g.stats.Errors++
+			g.stats.Error = o.err.Error()
+			continue
+		}
+		g.replayed = append(g.replayed, o.duration)
+	}
+
+	for _, g := range groups {
+		g.stats.Recorded = bench.Summarize(g.recorded)
+		g.stats.Replayed = bench.Summarize(g.replayed)
+		if g.stats.Recorded.P50 > 0 && len(g.replayed) > 0 {
+			g.stats.Change = (g.stats.Replayed.P50 - g.stats.Recorded.P50) / g.stats.Recorded.P50
+		}
+		result.Results = append(result.Results, g.stats)
+	}
+	return result
+}
@@ -0,0 +1,63 @@
+package replay
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	at := time.Date(2024, time.October, 1, 12, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Timestamp: at, Name: "A", SQL: "SELECT 1", Duration: 2},
+		{Timestamp: at.Add(time.Second), SQL: "SELECT  2"},
+		{Timestamp: at.Add(2 * time.Second), Name: "A", SQL: "SELECT 1", Duration: 4},
+		{Timestamp: at.Add(3 * time.Second), SQL: "SELECT 2"},
+		{Timestamp: at.Add(4 * time.Second), Name: "A", SQL: "SELECT 1", Duration: 2},
+		{Timestamp: at.Add(10 * time.Second), Name: "C", SQL: "SELECT 3", Duration: 1},
+	}
+	outcomes := []outcome{
+		{duration: 3 * time.Millisecond, lag: time.Millisecond},
+		{duration: 5 * time.Millisecond},
+		{duration: 3 * time.Millisecond, lag: 7 * time.Millisecond},
+		{err: errors.New("connection lost")},
+		{err: errors.New("canceled")},
+		{err: errors.New("timeout")},
+	}
+
+	r := summarize(entries, outcomes, 5*time.Second)
+	if r.Statements != 6 || r.Elapsed != 5 || r.Recorded != 10 || r.MaxLag != 7 {
+		t.Errorf("got %d statements, elapsed %v, recorded %v, max lag %v, want 6, 5, 10, 7",
+			r.Statements, r.Elapsed, r.Recorded, r.MaxLag)
+	}
+	if len(r.Results) != 3 {
+		t.Fatalf("got %d groups, want 3: %+v", len(r.Results), r.Results)
+	}
+
+	// Groups follow first appearance of statements in log.
+	a, b, c := r.Results[0], r.Results[1], r.Results[2]
+	if a.Statement != "A" || b.Statement != "SELECT 2" || c.Statement != "C" {
+		t.Errorf("got groups %q, %q, %q, want A, SELECT 2, C", a.Statement, b.Statement, c.Statement)
+	}
+
+	if a.Count != 3 || a.Errors != 1 || a.Error != "canceled" {
+		t.Errorf("A: got count %d, errors %d, error %q, want 3, 1, canceled", a.Count, a.Errors, a.Error)
+	}
+	if a.Recorded.P50 != 2 || a.Replayed.P50 != 3 || math.Abs(a.Change-0.5) > 1e-9 {
+		t.Errorf("A: got recorded median %v, replayed %v, change %v, want 2, 3, 0.5",
+			a.Recorded.P50, a.Replayed.P50, a.Change)
+	}
+
+	// Without recorded latency there is nothing to compare with.
+	if b.Count != 2 || b.Errors != 1 || b.Replayed.P50 != 5 || b.Change != 0 {
+		t.Errorf("SELECT 2: got count %d, errors %d, replayed median %v, change %v, want 2, 1, 5, 0",
+			b.Count, b.Errors, b.Replayed.P50, b.Change)
+	}
+
+	// Statements failed every time have no replayed latency either.
+	if c.Count != 1 || c.Errors != 1 || c.Error != "timeout" || c.Replayed.P50 != 0 || c.Change != 0 {
+		t.Errorf("C: got count %d, errors %d, error %q, replayed median %v, change %v, want 1, 1, timeout, 0, 0",
+			c.Count, c.Errors, c.Error, c.Replayed.P50, c.Change)
+	}
+}
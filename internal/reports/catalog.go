@@ -0,0 +1,442 @@
+package reports
+
+import (
+	"context"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+)
+
+var catalog = []Report{
+	{
+		Name:        "suppliers_of_commodity",
+		Task:        "1.1",
+		Description: "Найти всех поставщиков определенного товара и вывести о поставщиках инфо",
+		Params: []Param{
+			{Name: "commodity_name", Kind: Text, Description: "Exact name of commodity"},
+		},
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.SuppliersOfCommodity(ctx, args.text("commodity_name"))
+		},
+	},
+	{
+		Name:        "commodities_of_supplier",
+		Task:        "1.2",
+		Description: "Найти все товары определенного поставщика и вывести инфо о товарах",
+		Params: []Param{
+			{Name: "supplier_id", Kind: Int, Default: "1", Description: "Supplier id"},
+		},
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.CommoditiesOfSupplier(ctx, args.int32("supplier_id"))
+		},
+	},
+	{
+		Name:        "failed_card_payments",
+		Task:        "1.3",
+		Description: "Найти все карты по которым не прошла оплата и вывести инфо об этой операции",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.FailedCardPayments(ctx)
+		},
+	},
+	{
+		Name:        "orders_last_month",
+		Task:        "2.1",
+		Description: "Найти все заказы сделанные за последний месяц и вывести по ним инфу",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.OrdersLastMonth(ctx)
+		},
+	},
+	{
+		Name:        "courier_orders_in_month",
+		Task:        "2.2",
+		Description: "Найти всех курьеров которые работали в определенном месяце и году и вывести кол-во заказов, сделанных определенным курьером за весь месяц",
+		Params: []Param{
+			{Name: "year", Kind: Int, Default: "2024", Description: "Year of orders"},
+			{Name: "month", Kind: Int, Default: "3", Description: "Month of orders, 1-12"},
+		},
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.CourierOrdersInMonth(ctx, queries.CourierOrdersInMonthParams{
+				Year:  args.int32("year"),
+				Month: args.int32("month"),
+			})
+		},
+	},
+	{
+		Name:        "dishes_ordered_between",
+		Task:        "2.3",
+		Description: "Найти все блюда которые были заказаны с определенной даты по какую-то дату и вывести инфо о заказе с этим блюдом",
+		Params: []Param{
+			{Name: "since", Kind: Date, Default: "2024-03-01", Description: "First date of orders"},
+			{Name: "until", Kind: Date, Default: "2024-03-31", Description: "Last date of orders, inclusive"},
+		},
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.DishesOrderedBetween(ctx, queries.DishesOrderedBetweenParams{
+				Since: args.timestamp("since"),
+				Until: args.timestamp("until"),
+			})
+		},
+	},
+	{
+		Name:        "top_customer_by_spending",
+		Task:        "3.1",
+		Description: "Найти клиента который заказал на максимальную сумму за все время (без учета скидок)",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.TopCustomerBySpending(ctx)
+		},
+	},
+	{
+		Name:        "top_customer_by_discounted_spending",
+		Task:        "3.1",
+		Description: "Найти клиента который заказал на максимальную сумму за все время (с учетом скидок)",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.TopCustomerByDiscountedSpending(ctx)
+		},
+	},
+	{
+		Name:        "least_ordered_dishes",
+		Task:        "3.2",
+		Description: "Найти блюдо которое было заказано меньше всего раз за все время",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.LeastOrderedDishes(ctx)
+		},
+	},
+	{
+		Name:        "supplier_with_most_dishes_ordered",
+		Task:        "3.3",
+		Description: "Найти заведение из которого было заказано больше всего блюд за все время",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.SupplierWithMostDishesOrdered(ctx)
+		},
+	},
+	{
+		Name:        "dishes_with_ingredient",
+		Task:        "4.1",
+		Description: "Найти все блюда в составе которого есть определенный ингредиент",
+		Params: []Param{
+			{Name: "ingredient", Kind: Text, Description: "Ingredient substring"},
+		},
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.DishesWithIngredient(ctx, args.text("ingredient"))
+		},
+	},
+	{
+		Name:        "suppliers_on_street",
+		Task:        "4.2",
+		Description: "Найти все заведения на определенной улице",
+		Params: []Param{
+			{Name: "street", Kind: Text, Description: "Street name substring"},
+		},
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.SuppliersOnStreet(ctx, args.text("street"))
+		},
+	},
+	{
+		Name:        "users_by_surname_prefix",
+		Task:        "4.3",
+		Description: "Найти всех клиентов у которых фамилия начинается на определенную букву",
+		Params: []Param{
+			{Name: "prefix", Kind: Text, Description: "Surname prefix"},
+		},
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.UsersBySurnamePrefix(ctx, args.text("prefix"))
+		},
+	},
+	{
+		Name:        "unordered_supplier_dishes",
+		Task:        "5.1",
+		Description: "Найти блюда конкретного поставщика, которое не было заказано за последнюю неделю (через NOT IN)",
+		Params: []Param{
+			{Name: "supplier_id", Kind: Int, Default: "1", Description: "Supplier id"},
+		},
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.UnorderedSupplierDishes(ctx, args.int32("supplier_id"))
+		},
+	},
+	{
+		Name:        "unordered_supplier_dishes_except",
+		Task:        "5.1",
+		Description: "Найти блюда конкретного поставщика, которое не было заказано за последнюю неделю (через вычитание)",
+		Params: []Param{
+			{Name: "supplier_id", Kind: Int, Default: "1", Description: "Supplier id"},
+		},
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.UnorderedSupplierDishesExcept(ctx, args.int32("supplier_id"))
+		},
+	},
+	{
+		Name:        "unordered_supplier_dishes_count",
+		Task:        "5.1",
+		Description: "Найти блюда конкретного поставщика, которое не было заказано за последнюю неделю (через count)",
+		Params: []Param{
+			{Name: "supplier_id", Kind: Int, Default: "1", Description: "Supplier id"},
+		},
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.UnorderedSupplierDishesCount(ctx, args.int32("supplier_id"))
+		},
+	},
+	{
+		Name:        "users_without_restaurant_orders",
+		Task:        "5.2",
+		Description: "Найти клиентов, которые никогда не заказывали из ресторанов (через NOT EXISTS)",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.UsersWithoutRestaurantOrders(ctx)
+		},
+	},
+	{
+		Name:        "users_without_restaurant_orders_count",
+		Task:        "5.2",
+		Description: "Найти клиентов, которые никогда не заказывали из ресторанов (через count)",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.UsersWithoutRestaurantOrdersCount(ctx)
+		},
+	},
+	{
+		Name:        "users_never_paid_by_card",
+		Task:        "5.3",
+		Description: "Найти пользователей, которые никогда не платили картой (через NOT EXISTS)",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.UsersNeverPaidByCard(ctx)
+		},
+	},
+	{
+		Name:        "users_never_paid_by_card_except",
+		Task:        "5.3",
+		Description: "Найти пользователей, которые никогда не платили картой (через вычитание)",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.UsersNeverPaidByCardExcept(ctx)
+		},
+	},
+	{
+		Name:        "single_supplier_customers",
+		Task:        "6.1",
+		Description: "Найти клиентов, которые делают заказы только из одного заведения",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.SingleSupplierCustomers(ctx)
+		},
+	},
+	{
+		Name:        "dishes_from_single_supplier",
+		Task:        "6.2",
+		Description: "Найти блюда, которые предлагаются только в одном заведении",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.DishesFromSingleSupplier(ctx)
+		},
+	},
+	{
+		Name:        "single_address_customers",
+		Task:        "6.3",
+		Description: "Найти клиентов, которые заказывают доставку только на один адрес",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.SingleAddressCustomers(ctx)
+		},
+	},
+	{
+		Name:        "top_customers_by_spending",
+		Task:        "7.1",
+		Description: "Найти 3 клиента у которых макс сумма заказов по цене за все время",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.TopCustomersBySpending(ctx)
+		},
+	},
+	{
+		Name:        "least_selling_suppliers_last_month",
+		Task:        "7.2",
+		Description: "Найти 5 производителей, которые продали блюд на наименьшую сумму за последний месяц",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.LeastSellingSuppliersLastMonth(ctx)
+		},
+	},
+	{
+		Name:        "most_ordered_category",
+		Task:        "7.3",
+		Description: "Найти 1 категорию, в которой было заказано больше всего блюд за все время",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.MostOrderedCategory(ctx)
+		},
+	},
+	{
+		Name:        "avg_monthly_orders_per_user",
+		Task:        "8.1",
+		Description: "Найти среднее количество заказов клиента в месяц",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.AvgMonthlyOrdersPerUser(ctx)
+		},
+	},
+	{
+		Name:        "avg_positions_per_order",
+		Task:        "8.2",
+		Description: "Найти среднее количество позиций в заказе",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			value, err := q.AvgPositionsPerOrder(ctx)
+			return []struct{ AvgPositionsPerOrder float64 }{{value}}, err
+		},
+	},
+	{
+		Name:        "avg_dishes_per_supplier",
+		Task:        "8.3",
+		Description: "Найти среднее количество блюд на продавца",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			value, err := q.AvgDishesPerSupplier(ctx)
+			return []struct{ AvgDishesPerSupplier float64 }{{value}}, err
+		},
+	},
+	{
+		Name:        "top_suppliers_by_rating",
+		Task:        "9.1",
+		Description: "Найти топ-3 поставщиков по среднему рейтингу их блюд и товаров вместе",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.TopSuppliersByRating(ctx)
+		},
+	},
+	{
+		Name:        "supplier_price_ranges",
+		Task:        "9.2",
+		Description: "Для каждого поставщика посчитать количество блюд и товаров в разных ценовых диапазонах (до 500, 500-1000, больше 1000)",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.SupplierPriceRanges(ctx)
+		},
+	},
+	{
+		Name:        "suppliers_above_avg_dish_rating",
+		Task:        "9.3",
+		Description: "Найти поставщиков, у которых средний рейтинг блюд выше среднего рейтинга по всем блюдам в системе",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.SuppliersAboveAvgDishRating(ctx)
+		},
+	},
+	{
+		Name:        "user_monthly_orders",
+		Task:        "10.1",
+		Description: "Найти пользователей и количество их заказов за каждый месяц года",
+		Params: []Param{
+			{Name: "year", Kind: Int, Default: "2024", Description: "Year of orders"},
+		},
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.UserMonthlyOrders(ctx, args.int32("year"))
+		},
+	},
+	{
+		Name:        "users_ordering_every_month",
+		Task:        "10.2",
+		Description: "Определить пользователей, которые сделали заказы во все месяцы года",
+		Params: []Param{
+			{Name: "year", Kind: Int, Default: "2024", Description: "Year of orders"},
+		},
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.UsersOrderingEveryMonth(ctx, args.int32("year"))
+		},
+	},
+	{
+		Name:        "supplier_best_month",
+		Task:        "10.3",
+		Description: "Найти месяц в году с наибольшим количеством заказов для каждого поставщика",
+		Params: []Param{
+			{Name: "year", Kind: Int, Default: "2024", Description: "Year of orders"},
+		},
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.SupplierBestMonth(ctx, args.int32("year"))
+		},
+	},
+	{
+		Name:        "users_with_addresses_and_cards",
+		Task:        "11.1",
+		Description: "Найти пользователей, у которых есть и сохраненные адреса, и привязанные карты",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.UsersWithAddressesAndCards(ctx)
+		},
+	},
+	{
+		Name:        "users_with_many_addresses_no_cards",
+		Task:        "11.2",
+		Description: "Найти пользователей, у которых больше 3 сохраненных адресов, но нет привязанных карт",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.UsersWithManyAddressesNoCards(ctx)
+		},
+	},
+	{
+		Name:        "users_not_using_saved_addresses",
+		Task:        "11.3",
+		Description: "Определить пользователей, которые никогда не использовали свои сохраненные адреса для заказов",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.UsersNotUsingSavedAddresses(ctx)
+		},
+	},
+	{
+		Name:        "dishes_offered_by_many_suppliers",
+		Task:        "12.1",
+		Description: "Найти блюда, которые есть у более чем 2 поставщиков, и их среднюю цену",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.DishesOfferedByManySuppliers(ctx)
+		},
+	},
+	{
+		Name:        "suppliers_with_all_dishes_above_avg",
+		Task:        "12.2",
+		Description: "Определить поставщиков, у которых все блюда дороже средней цены по всем блюдам",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.SuppliersWithAllDishesAboveAvg(ctx)
+		},
+	},
+	{
+		Name:        "supplier_dish_price_spread",
+		Task:        "12.3",
+		Description: "Найти поставщиков с наибольшей разницей в цене между их самым дорогим и самым дешевым блюдом",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.SupplierDishPriceSpread(ctx)
+		},
+	},
+	{
+		Name:        "top_rated_busy_suppliers",
+		Task:        "13.1",
+		Description: "Найти поставщиков, у которых средний рейтинг блюд выше 4.5 И количество блюд больше 5",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.TopRatedBusySuppliers(ctx)
+		},
+	},
+	{
+		Name:        "expensive_categories",
+		Task:        "13.2",
+		Description: "Найти категории, в которых есть более 10 блюд И средняя цена блюд выше общей средней цены",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.ExpensiveCategories(ctx)
+		},
+	},
+	{
+		Name:        "frequent_big_spenders",
+		Task:        "13.3",
+		Description: "Найти пользователей, сделавших более 3 заказов И средняя сумма заказа которых выше 1000",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.FrequentBigSpenders(ctx)
+		},
+	},
+	{
+		Name:        "supplier_size_categories",
+		Task:        "14.1",
+		Description: "Классифицировать поставщиков по количеству блюд: мало (< 5), средне (5-15), много (> 15)",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.SupplierSizeCategories(ctx)
+		},
+	},
+	{
+		Name:        "orders_by_time_of_day",
+		Task:        "14.2",
+		Description: "Разделить заказы на категории по времени доставки: утро (6-12), день (12-18), вечер (18-24), ночь (0-6)",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.OrdersByTimeOfDay(ctx)
+		},
+	},
+	{
+		Name:        "user_activity_last_month",
+		Task:        "14.3",
+		Description: "Категоризировать пользователей по частоте заказов за последний месяц: активные (> 5), средние (2-5), редкие (< 2)",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.UserActivityLastMonth(ctx)
+		},
+	},
+	{
+		Name:        "delivery_durations",
+		Description: "Время доставки заказов по месяцам создания: среднее, медиана и максимум в минутах от создания до доставки",
+		run: func(ctx context.Context, q *queries.Queries, args Args) (any, error) {
+			return q.DeliveryDurations(ctx)
+		},
+	},
+}
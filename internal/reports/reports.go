@@ -0,0 +1,147 @@
+package reports
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+)
+
+type Kind string
+
+const (
+	Int  Kind = "int"
+	Text Kind = "text"
+	Date Kind = "date"
+)
+
+// Param describes a report parameter. Parameters without default value are
+// required.
+type Param struct {
+	Name        string
+	Kind        Kind
+	Default     string
+	Description string
+}
+
+// Report is a named analytical query. Task is the number of task from
+// tasks.txt the report solves, empty for reports not coming from there.
+type Report struct {
+	Name        string
+	Task        string
+	Description string
+	Params      []Param
+
+	run func(ctx context.Context, q *queries.Queries, args Args) (any, error)
+}
+
+// Args holds parsed report parameters keyed by name.
+type Args map[string]any
+
+func (a Args) int32(name string) int32 {
+	return a[name].(int32)
+}
+
+func (a Args) text(name string) string {
+	return a[name].(string)
+}
+
+func (a Args) timestamp(name string) pgtype.Timestamp {
+	return pgtype.Timestamp{Time: a[name].(time.Time), Valid: true}
+}
+
+// All returns all reports in order of tasks.
+func All() []Report {
+	return slices.Clone(catalog)
+}
+
+// Find returns report with given name.
+func Find(name string) (Report, error) {
+	idx := slices.IndexFunc(catalog, func(r Report) bool { return r.Name == name })
+	if idx == -1 {
+		return Report{}, fmt.Errorf("unknown report %q", name)
+	}
+	return catalog[idx], nil
+}
+
+// ParseArgs converts raw parameter values into typed arguments, filling in
+// defaults for missing ones.
+func (r Report) ParseArgs(raw map[string]string) (Args, error) {
+	for name := range raw {
+		if !slices.ContainsFunc(r.Params, func(p Param) bool { return p.Name == name }) {
+			return nil, fmt.Errorf("report %q has no parameter %q", r.Name, name)
+		}
+	}
+
+	args := make(Args, len(r.Params))
+	for _, p := range r.Params {
+		value, ok := raw[p.Name]
+		if !ok {
+			if p.Default == "" {
+				return nil, fmt.Errorf("missing required parameter %q", p.Name)
+			}
+			value = p.Default
+		}
+
+		parsed, err := p.parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("parse parameter %q: %w", p.Name, err)
+		}
+		args[p.Name] = parsed
+	}
+	return args, nil
+}
+
+// SelectParams returns subset of params which report accepts, so that one set
+// of values may be shared between many reports.
+func (r Report) SelectParams(params map[string]string) map[string]string {
+	selected := make(map[string]string)
+	for _, p := range r.Params {
+		if value, ok := params[p.Name]; ok {
+			selected[p.Name] = value
+		}
+	}
+	return selected
+}
+
+func (p Param) parse(value string) (any, error) {
+	switch p.Kind {
+	case Int:
+		n, err := strconv.ParseInt(value, 10, 32)
+		return int32(n), err
+	case Text:
+		return value, nil
+	case Date:
+		return time.Parse(time.DateOnly, value)
+	default:
+		return nil, fmt.Errorf("unknown parameter kind %q", p.Kind)
+	}
+}
+
+// Run executes report with already parsed arguments.
+func (r Report) Run(ctx context.Context, q *queries.Queries, args Args) (*Result, error) {
+	rows, err := r.run(ctx, q, args)
+	if err != nil {
+		return nil, err
+	}
+	return newResult(rows)
+}
+
+// ParseParams splits key=value pairs as given on command line.
+func ParseParams(pairs []string) (map[string]string, error) {
+	params := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("parameter %q is not in key=value form", pair)
+		}
+		params[key] = value
+	}
+	return params, nil
+}
@@ -0,0 +1,176 @@
+package reports
+
+import (
+	"database/sql/driver"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode"
+)
+
+// Result is a report output converted to plain columns and values, where
+// values are nil, string, int64, float64, bool or time.Time.
+type Result struct {
+	Columns []string
+	Rows    [][]any
+}
+
+// newResult converts slice of structs returned by generated queries into
+// Result, using snake-cased field names as column names.
+func newResult(rows any) (*Result, error) {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("unsupported report rows type %T", rows)
+	}
+
+	typ := v.Type().Elem()
+	result := &Result{
+		Columns: make([]string, typ.NumField()),
+		Rows:    make([][]any, v.Len()),
+	}
+	for i := range typ.NumField() {
+		result.Columns[i] = snakeCase(typ.Field(i).Name)
+	}
+	for i := range v.Len() {
+		row := make([]any, typ.NumField())
+		for j := range typ.NumField() {
+			value, err := plainValue(v.Index(i).Field(j).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("convert column %q: %w", result.Columns[j], err)
+			}
+			row[j] = value
+		}
+		result.Rows[i] = row
+	}
+	return result, nil
+}
+
+func plainValue(value any) (any, error) {
+	switch v := value.(type) {
+	case driver.Valuer:
+		return v.Value()
+	case []byte:
+		if v == nil {
+			return nil, nil
+		}
+		return `\x` + hex.EncodeToString(v), nil
+	case int32:
+		return int64(v), nil
+	default:
+		return v, nil
+	}
+}
+
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.Format(time.DateTime)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (r *Result) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(r.Columns, "\t")))
+	for _, row := range r.Rows {
+		cells := make([]string, len(row))
+		for i, value := range row {
+			cells[i] = formatValue(value)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "(%d rows)\n", len(r.Rows))
+	return err
+}
+
+func (r *Result) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(r.Columns); err != nil {
+		return err
+	}
+	for _, row := range r.Rows {
+		cells := make([]string, len(row))
+		for i, value := range row {
+			cells[i] = formatValue(value)
+		}
+		if err := cw.Write(cells); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes rows as array of objects, keeping keys in column order.
+func (r *Result) WriteJSON(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("[")
+	for i, row := range r.Rows {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n  {")
+		for j, value := range row {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			key, _ := json.Marshal(r.Columns[j])
+			val, err := json.Marshal(value)
+			if err != nil {
+				return fmt.Errorf("encode column %q: %w", r.Columns[j], err)
+			}
+			b.Write(key)
+			b.WriteString(": ")
+			b.Write(val)
+		}
+		b.WriteString("}")
+	}
+	if len(r.Rows) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString("]\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Write renders result in one of table, csv or json formats.
+func (r *Result) Write(w io.Writer, format string) error {
+	switch format {
+	case "table":
+		return r.WriteTable(w)
+	case "csv":
+		return r.WriteCSV(w)
+	case "json":
+		return r.WriteJSON(w)
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
@@ -0,0 +1,65 @@
+package reports
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+)
+
+var errCaptured = errors.New("statement captured")
+
+// Statement is SQL of a report together with its positional arguments, as
+// sent to postgres by the generated query.
+type Statement struct {
+	SQL  string
+	Args []any
+}
+
+// Statement returns SQL and arguments the report would execute, without
+// touching the database.
+func (r Report) Statement(args Args) (Statement, error) {
+	capture := &capturingDB{}
+	_, err := r.run(context.Background(), queries.New(capture), args)
+	if !errors.Is(err, errCaptured) {
+		if err == nil {
+			err = errors.New("report did not execute any statement")
+		}
+		return Statement{}, err
+	}
+	return capture.statement, nil
+}
+
+// capturingDB implements queries.DBTX, remembering first statement passed to
+// it and failing it with errCaptured.
+type capturingDB struct {
+	statement Statement
+}
+
+func (c *capturingDB) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	c.statement = Statement{SQL: sql, Args: args}
+	return pgconn.CommandTag{}, errCaptured
+}
+
+func (c *capturingDB) Query(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+	c.statement = Statement{SQL: sql, Args: args}
+	return nil, errCaptured
+}
+
+func (c *capturingDB) QueryRow(_ context.Context, sql string, args ...any) pgx.Row {
+	c.statement = Statement{SQL: sql, Args: args}
+	return capturedRow{}
+}
+
+func (c *capturingDB) CopyFrom(context.Context, pgx.Identifier, []string, pgx.CopyFromSource) (int64, error) {
+	return 0, errCaptured
+}
+
+type capturedRow struct{}
+
+func (capturedRow) Scan(...any) error {
+	return errCaptured
+}
@@ -17,6 +17,7 @@ var tableNames = []string{
 	"user_cards",
 	"orders",
 	"orders_composition",
+	"order_status_history",
 	"payments",
 	"couriers",
 	"dishes",
@@ -0,0 +1,184 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Stats struct {
+	Tables              []Table     `json:"tables"`
+	NullableForeignKeys []NullRatio `json:"nullable_foreign_keys"`
+	OrderStatuses       []Share     `json:"order_statuses"`
+	PaymentMethods      []Share     `json:"payment_methods"`
+	Orders              TimeRange   `json:"orders"`
+}
+
+// Table sizes are in bytes and include all partitions of partitioned tables.
+type Table struct {
+	Name       string `json:"name"`
+	Rows       int64  `json:"rows"`
+	HeapSize   int64  `json:"heap_size"`
+	IndexSize  int64  `json:"index_size"`
+	ToastSize  int64  `json:"toast_size"`
+	TotalSize  int64  `json:"total_size"`
+	Partitions int    `json:"partitions,omitempty"`
+}
+
+type NullRatio struct {
+	Table  string  `json:"table"`
+	Column string  `json:"column"`
+	Nulls  int64   `json:"nulls"`
+	Rows   int64   `json:"rows"`
+	Ratio  float64 `json:"ratio"`
+	Target string  `json:"target"`
+}
+
+type Share struct {
+	Value string  `json:"value"`
+	Count int64   `json:"count"`
+	Ratio float64 `json:"ratio"`
+}
+
+type TimeRange struct {
+	From *time.Time `json:"from"`
+	To   *time.Time `json:"to"`
+}
+
+func Collect(ctx context.Context, pg *pgxpool.Pool) (*Stats, error) {
+	var s Stats
+	var err error
+
+	if s.Tables, err = collectTables(ctx, pg); err != nil {
+		return nil, fmt.Errorf("collect table stats: %w", err)
+	}
+	if s.NullableForeignKeys, err = collectNullRatios(ctx, pg); err != nil {
+		return nil, fmt.Errorf("collect null ratios: %w", err)
+	}
+	if s.OrderStatuses, err = collectShares(ctx, pg, "orders", "status"); err != nil {
+		return nil, fmt.Errorf("collect order statuses: %w", err)
+	}
+	if s.PaymentMethods, err = collectShares(ctx, pg, "payments", "method"); err != nil {
+		return nil, fmt.Errorf("collect payment methods: %w", err)
+	}
+	err = pg.QueryRow(ctx, `SELECT min(timestamp), max(timestamp) FROM orders`).Scan(&s.Orders.From, &s.Orders.To)
+	if err != nil {
+		return nil, fmt.Errorf("collect orders time range: %w", err)
+	}
+
+	return &s, nil
+}
+
+func collectTables(ctx context.Context, pg *pgxpool.Pool) ([]Table, error) {
+	rows, err := pg.Query(ctx, `
+		WITH relations AS (
+			SELECT parent.relname AS name, parent.oid AS relid
+			FROM pg_class parent
+			WHERE parent.relnamespace = 'public'::regnamespace
+			  AND parent.relkind IN ('r', 'p')
+			  AND NOT parent.relispartition
+			UNION ALL
+			SELECT parent.relname, pg_inherits.inhrelid
+			FROM pg_class parent
+			JOIN pg_inherits ON pg_inherits.inhparent = parent.oid
+			WHERE parent.relnamespace = 'public'::regnamespace
+			  AND parent.relkind = 'p'
+		)
+		SELECT r.name,
+		       sum(pg_table_size(r.relid) - coalesce(pg_total_relation_size(nullif(c.reltoastrelid, 0)), 0))::BIGINT,
+		       sum(pg_indexes_size(r.relid))::BIGINT,
+		       sum(coalesce(pg_total_relation_size(nullif(c.reltoastrelid, 0)), 0))::BIGINT,
+		       (count(*) - 1)::INT
+		FROM relations r
+		JOIN pg_class c ON c.oid = r.relid
+		GROUP BY r.name
+		ORDER BY r.name`)
+	if err != nil {
+		return nil, err
+	}
+	tables, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (Table, error) {
+		var t Table
+		err := row.Scan(&t.Name, &t.HeapSize, &t.IndexSize, &t.ToastSize, &t.Partitions)
+		t.TotalSize = t.HeapSize + t.IndexSize + t.ToastSize
+		return t, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range tables {
+		query := "SELECT count(*) FROM " + pgx.Identifier{tables[i].Name}.Sanitize()
+		if err := pg.QueryRow(ctx, query).Scan(&tables[i].Rows); err != nil {
+			return nil, fmt.Errorf("count rows of %q: %w", tables[i].Name, err)
+		}
+	}
+	return tables, nil
+}
+
+func collectNullRatios(ctx context.Context, pg *pgxpool.Pool) ([]NullRatio, error) {
+	rows, err := pg.Query(ctx, `
+		SELECT t.relname, a.attname, c.confrelid::regclass::TEXT
+		FROM pg_constraint c
+		JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = c.conkey[1]
+		JOIN pg_class t ON t.oid = c.conrelid
+		WHERE c.contype = 'f'
+		  AND c.connamespace = 'public'::regnamespace
+		  AND c.conparentid = 0
+		  AND cardinality(c.conkey) = 1
+		  AND NOT a.attnotnull
+		  AND NOT t.relispartition
+		ORDER BY 1, 2`)
+	if err != nil {
+		return nil, err
+	}
+	ratios, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (NullRatio, error) {
+		var r NullRatio
+		err := row.Scan(&r.Table, &r.Column, &r.Target)
+		return r, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range ratios {
+		r := &ratios[i]
+		query := fmt.Sprintf(
+			"SELECT count(*) FILTER (WHERE %s IS NULL), count(*) FROM %s",
+			pgx.Identifier{r.Column}.Sanitize(), pgx.Identifier{r.Table}.Sanitize(),
+		)
+		if err := pg.QueryRow(ctx, query).Scan(&r.Nulls, &r.Rows); err != nil {
+			return nil, fmt.Errorf("count nulls in %s.%s: %w", r.Table, r.Column, err)
+		}
+		if r.Rows > 0 {
+			r.Ratio = float64(r.Nulls) / float64(r.Rows)
+		}
+	}
+	return ratios, nil
+}
+
+func collectShares(ctx context.Context, pg *pgxpool.Pool, table, column string) ([]Share, error) {
+	query := fmt.Sprintf(`
+		SELECT %[1]s::TEXT, count(*), count(*)::FLOAT8 / sum(count(*)) OVER ()
+		FROM %[2]s
+		GROUP BY %[1]s
+		ORDER BY 2 DESC`,
+		pgx.Identifier{column}.Sanitize(), pgx.Identifier{table}.Sanitize(),
+	)
+	rows, err := pg.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (Share, error) {
+		var s Share
+		var value *string
+		err := row.Scan(&value, &s.Count, &s.Ratio)
+		s.Value = "NULL"
+		if value != nil {
+			s.Value = *value
+		}
+		return s, err
+	})
+}
@@ -0,0 +1,178 @@
+package tempdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/migrations"
+)
+
+var (
+	// ErrNotInstalled is returned when initdb or postgres binaries can't be
+	// found. Callers are expected to skip their work rather than fail.
+	ErrNotInstalled = errors.New("postgres binaries not found")
+	// ErrRoot is returned when run by root, since postgres refuses to run
+	// as root. Callers are expected to skip their work rather than fail.
+	ErrRoot = errors.New("postgres can't be run as root")
+)
+
+const startTimeout = 30 * time.Second
+
+// Server is a disposable postgres cluster living in a temporary directory
+// and listening on a unix socket only.
+type Server struct {
+	dir  string
+	cmd  *exec.Cmd
+	logs *os.File
+
+	Pool *pgxpool.Pool
+}
+
+// Start initializes a fresh cluster, starts postgres on it and applies
+// schema from schemaPath followed by all migrations. It returns
+// ErrNotInstalled or ErrRoot when postgres can't be started here at all.
+func Start(ctx context.Context, schemaPath string) (*Server, error) {
+	schema, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return nil, fmt.Errorf("read schema: %w", err)
+	}
+
+	bin, err := findBinaries()
+	if err != nil {
+		return nil, err
+	}
+	if os.Geteuid() == 0 {
+		return nil, ErrRoot
+	}
+
+	dir, err := os.MkdirTemp("", "ctrl-pg-")
+	if err != nil {
+		return nil, fmt.Errorf("create temp dir: %w", err)
+	}
+	s := &Server{dir: dir}
+
+	data := filepath.Join(dir, "data")
+	initdb := exec.CommandContext(ctx, filepath.Join(bin, "initdb"),
+		"-D", data, "-U", "postgres", "--auth=trust", "--encoding=UTF8", "--no-sync")
+	if out, err := initdb.CombinedOutput(); err != nil {
+		s.Stop()
+		return nil, fmt.Errorf("initdb: %w: %s", err, out)
+	}
+
+	log.Printf("Starting temporary postgres in %q", dir)
+	s.cmd = exec.Command(filepath.Join(bin, "postgres"),
+		"-D", data,
+		"-k", dir,
+		"-c", "listen_addresses=",
+		"-c", "fsync=off",
+		"-c", "synchronous_commit=off",
+		"-c", "full_page_writes=off",
+	)
+	if s.logs, err = os.Create(filepath.Join(dir, "postgres.log")); err != nil {
+		s.Stop()
+		return nil, fmt.Errorf("create log file: %w", err)
+	}
+	s.cmd.Stderr = s.logs
+	if err := s.cmd.Start(); err != nil {
+		s.Stop()
+		return nil, fmt.Errorf("start postgres: %w", err)
+	}
+
+	config, err := pgxpool.ParseConfig(fmt.Sprintf("host=%s user=postgres dbname=postgres", dir))
+	if err != nil {
+		s.Stop()
+		return nil, err
+	}
+	if s.Pool, err = pgxpool.NewWithConfig(ctx, config); err != nil {
+		s.Stop()
+		return nil, err
+	}
+	if err := s.waitReady(ctx); err != nil {
+		s.Stop()
+		return nil, err
+	}
+
+	if _, err := s.Pool.Exec(ctx, string(schema)); err != nil {
+		s.Stop()
+		return nil, fmt.Errorf("apply schema: %w", err)
+	}
+	if _, err := migrations.Apply(ctx, s.Pool); err != nil {
+		s.Stop()
+		return nil, fmt.Errorf("apply migrations: %w", err)
+	}
+	return s, nil
+}
+
+func (s *Server) waitReady(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, startTimeout)
+	defer cancel()
+
+	for {
+		err := s.Pool.Ping(ctx)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			logs, _ := os.ReadFile(filepath.Join(s.dir, "postgres.log"))
+			return fmt.Errorf("wait for postgres: %w: %s", err, logs)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// Stop shuts postgres down and removes its directory.
+func (s *Server) Stop() error {
+	if s.Pool != nil {
+		s.Pool.Close()
+	}
+	var err error
+	if s.cmd != nil && s.cmd.Process != nil {
+		// SIGINT is postgres "fast" shutdown mode.
+		if err = s.cmd.Process.Signal(syscall.SIGINT); err == nil {
+			err = s.cmd.Wait()
+		}
+	}
+	if s.logs != nil {
+		s.logs.Close()
+	}
+	return errors.Join(err, os.RemoveAll(s.dir))
+}
+
+// findBinaries returns directory containing initdb and postgres, looking in
+// PATH, pg_config --bindir and usual locations of distribution packages.
+func findBinaries() (string, error) {
+	var candidates []string
+	if path, err := exec.LookPath("initdb"); err == nil {
+		candidates = append(candidates, filepath.Dir(path))
+	}
+	if out, err := exec.Command("pg_config", "--bindir").Output(); err == nil {
+		candidates = append(candidates, strings.TrimSpace(string(out)))
+	}
+	versioned, _ := filepath.Glob("/usr/lib/postgresql/*/bin")
+	slices.Reverse(versioned)
+	candidates = append(candidates, versioned...)
+
+	for _, dir := range candidates {
+		if isExecutable(filepath.Join(dir, "initdb")) && isExecutable(filepath.Join(dir, "postgres")) {
+			return dir, nil
+		}
+	}
+	return "", ErrNotInstalled
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir() && info.Mode()&0o111 != 0
+}
@@ -0,0 +1,32 @@
+// Package tempdbtest provides test helpers built on tempdb, kept apart so
+// that tempdb itself does not import testing.
+package tempdbtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/LeKSuS-04/mephi-db/internal/tempdb"
+)
+
+// Start starts a disposable postgres with schema from schemaPath and stops
+// it when tb finishes. Test is skipped if postgres can't be run here, either
+// because it is not installed or because tests are run by root.
+func Start(tb testing.TB, schemaPath string) *tempdb.Server {
+	tb.Helper()
+
+	server, err := tempdb.Start(context.Background(), schemaPath)
+	if errors.Is(err, tempdb.ErrNotInstalled) || errors.Is(err, tempdb.ErrRoot) {
+		tb.Skip(err)
+	}
+	if err != nil {
+		tb.Fatalf("start temporary postgres: %v", err)
+	}
+	tb.Cleanup(func() {
+		if err := server.Stop(); err != nil {
+			tb.Logf("stop temporary postgres: %v", err)
+		}
+	})
+	return server
+}
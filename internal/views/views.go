@@ -0,0 +1,38 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/LeKSuS-04/mephi-db/internal/db/queries"
+)
+
+// Names of materialized views created by migrations, refreshed in order.
+var Names = []string{
+	"order_totals",
+	"user_monthly_orders",
+	"supplier_monthly_sales",
+}
+
+// Refresh recomputes all materialized views. Concurrent refresh doesn't block
+// readers, but is slower and requires views to be populated already.
+func Refresh(ctx context.Context, db queries.DBTX, concurrently bool) error {
+	for _, name := range Names {
+		query := "REFRESH MATERIALIZED VIEW "
+		if concurrently {
+			query += "CONCURRENTLY "
+		}
+		query += pgx.Identifier{name}.Sanitize()
+
+		start := time.Now()
+		if _, err := db.Exec(ctx, query); err != nil {
+			return fmt.Errorf("refresh view %q: %w", name, err)
+		}
+		log.Printf("Refreshed view %q in %s", name, time.Since(start).Round(time.Millisecond))
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package future
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Tuple2[A, B any] struct {
+	First  A
+	Second B
+}
+
+type Tuple3[A, B, C any] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+// Go runs f in a new goroutine and returns future resolved with its result.
+// Error of f cancels future with it, panic cancels future with error
+// describing it.
+func Go[T any](f func() (T, error)) *Future[T] {
+	fut := New[T]()
+	go func() {
+		defer func() {
+			if v := recover(); v != nil {
+				fut.CancelWithError(fmt.Errorf("panic: %v", v))
+			}
+		}()
+		value, err := f()
+		if err != nil {
+			fut.CancelWithError(err)
+			return
+		}
+		fut.Set(value)
+	}()
+	return fut
+}
+
+// Then returns future resolved with result of fn applied to value of f.
+// If f is canceled, fn is not called and returned future is canceled with
+// the same cause.
+func Then[T, U any](f *Future[T], fn func(T) (U, error)) *Future[U] {
+	return Go(func() (U, error) {
+		value, err := f.Get()
+		if err != nil {
+			return *new(U), err
+		}
+		return fn(value)
+	})
+}
+
+// All returns future resolved with values of fs in the same order. It is
+// canceled as soon as any of fs is canceled, with its cause.
+//
+// Like every combinator waiting for several futures, All never resolves if
+// one of fs is never resolved and none is canceled, and the goroutine
+// waiting for them stays blocked until then. Producers are expected to
+// cancel futures they can't set.
+func All[T any](fs ...*Future[T]) *Future[[]T] {
+	waiters := make([]waiter, len(fs))
+	for i, f := range fs {
+		waiters[i] = f
+	}
+	return Go(func() ([]T, error) {
+		if err := wait(waiters...); err != nil {
+			return nil, err
+		}
+		values := make([]T, len(fs))
+		for i, f := range fs {
+			values[i], _ = f.result()
+		}
+		return values, nil
+	})
+}
+
+// Zip2 is All for futures of different types.
+func Zip2[A, B any](a *Future[A], b *Future[B]) *Future[Tuple2[A, B]] {
+	return Go(func() (Tuple2[A, B], error) {
+		if err := wait(a, b); err != nil {
+			return Tuple2[A, B]{}, err
+		}
+		var t Tuple2[A, B]
+		t.First, _ = a.result()
+		t.Second, _ = b.result()
+		return t, nil
+	})
+}
+
+// Zip3 is All for futures of different types.
+func Zip3[A, B, C any](a *Future[A], b *Future[B], c *Future[C]) *Future[Tuple3[A, B, C]] {
+	return Go(func() (Tuple3[A, B, C], error) {
+		if err := wait(a, b, c); err != nil {
+			return Tuple3[A, B, C]{}, err
+		}
+		var t Tuple3[A, B, C]
+		t.First, _ = a.result()
+		t.Second, _ = b.result()
+		t.Third, _ = c.result()
+		return t, nil
+	})
+}
+
+// Any returns future resolved with value of the first of fs to be set. It
+// is canceled with all of their causes joined if every one of fs is
+// canceled, or with ErrFutureCanceled if fs is empty.
+func Any[T any](fs ...*Future[T]) *Future[T] {
+	return Go(func() (T, error) {
+		if len(fs) == 0 {
+			return *new(T), ErrFutureCanceled
+		}
+		stop := make(chan struct{})
+		defer close(stop)
+		done := notify(fs, stop)
+		errs := make([]error, 0, len(fs))
+		for range fs {
+			f := fs[<-done]
+			value, err := f.result()
+			if err == nil {
+				return value, nil
+			}
+			errs = append(errs, err)
+		}
+		return *new(T), errors.Join(errs...)
+	})
+}
+
+type waiter interface {
+	Done() <-chan struct{}
+	failure() error
+}
+
+func (m *Future[T]) failure() error {
+	_, err := m.result()
+	return err
+}
+
+// wait blocks until every one of ws is set, or returns cause of the first
+// one canceled.
+func wait(ws ...waiter) error {
+	stop := make(chan struct{})
+	defer close(stop)
+	done := notify(ws, stop)
+	for range ws {
+		if err := ws[<-done].failure(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notify sends index of every one of ws to returned channel once it is
+// resolved. Watching goroutines exit once stop is closed, so that ws which
+// are never resolved do not keep them alive after caller has its answer.
+func notify[W interface{ Done() <-chan struct{} }](ws []W, stop <-chan struct{}) <-chan int {
+	done := make(chan int, len(ws))
+	for i, w := range ws {
+		go func() {
+			select {
+			case <-w.Done():
+				done <- i
+			case <-stop:
+			}
+		}()
+	}
+	return done
+}
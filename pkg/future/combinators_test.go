@@ -0,0 +1,240 @@
+package future
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	errFirst  = errors.New("first")
+	errSecond = errors.New("second")
+)
+
+// checkNoLeaks fails t if goroutines started during the test are still
+// running after it, giving them a second to finish.
+func checkNoLeaks(t *testing.T) {
+	t.Helper()
+	before := runtime.NumGoroutine()
+	t.Cleanup(func() {
+		deadline := time.Now().Add(time.Second)
+		for runtime.NumGoroutine() > before {
+			if time.Now().After(deadline) {
+				buf := make([]byte, 1<<20)
+				buf = buf[:runtime.Stack(buf, true)]
+				t.Errorf("%d goroutines leaked:\n%s", runtime.NumGoroutine()-before, buf)
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+}
+
+func mustGet[T any](t *testing.T, f *Future[T]) T {
+	t.Helper()
+	value, err := f.GetTimeout(time.Second)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	return value
+}
+
+func mustFail[T any](t *testing.T, f *Future[T], targets ...error) error {
+	t.Helper()
+	_, err := f.GetTimeout(time.Second)
+	if err == nil {
+		t.Fatal("get: expected error")
+	}
+	for _, target := range targets {
+		if !errors.Is(err, target) {
+			t.Errorf("error %q does not match %q", err, target)
+		}
+	}
+	return err
+}
+
+func resolved[T any](value T) *Future[T] {
+	f := New[T]()
+	f.Set(value)
+	return f
+}
+
+func canceled[T any](err error) *Future[T] {
+	f := New[T]()
+	f.CancelWithError(err)
+	return f
+}
+
+func TestGo(t *testing.T) {
+	checkNoLeaks(t)
+
+	if got := mustGet(t, Go(func() (int, error) { return 1, nil })); got != 1 {
+		t.Errorf("got %d, want 1", got)
+	}
+	mustFail(t, Go(func() (int, error) { return 0, errFirst }), errFirst, ErrFutureCanceled)
+
+	err := mustFail(t, Go(func() (int, error) { panic("boom") }), ErrFutureCanceled)
+	if err.Error() != "panic: boom" {
+		t.Errorf("got %q, want panic description", err)
+	}
+}
+
+func TestThen(t *testing.T) {
+	checkNoLeaks(t)
+
+	double := func(v int) (int, error) { return v * 2, nil }
+	if got := mustGet(t, Then(resolved(21), double)); got != 42 {
+		t.Errorf("got %d, want 42", got)
+	}
+
+	mustFail(t, Then(resolved(1), func(int) (int, error) { return 0, errSecond }), errSecond)
+
+	called := false
+	mustFail(t, Then(canceled[int](errFirst), func(v int) (int, error) {
+		called = true
+		return v, nil
+	}), errFirst, ErrFutureCanceled)
+	if called {
+		t.Error("fn called on canceled future")
+	}
+
+	f := New[int]()
+	f.Cancel()
+	mustFail(t, Then(f, double), ErrFutureCanceled)
+}
+
+func TestAll(t *testing.T) {
+	checkNoLeaks(t)
+
+	fs := []*Future[int]{New[int](), New[int](), New[int]()}
+	all := All(fs...)
+	for i := len(fs) - 1; i >= 0; i-- {
+		fs[i].Set(i)
+	}
+	got := mustGet(t, all)
+	if fmt.Sprint(got) != "[0 1 2]" {
+		t.Errorf("got %v, want values in order of futures", got)
+	}
+
+	if got := mustGet(t, All[int]()); len(got) != 0 {
+		t.Errorf("got %v for no futures, want empty", got)
+	}
+}
+
+func TestAllShortCircuits(t *testing.T) {
+	checkNoLeaks(t)
+
+	// Second future is never resolved, All must not wait for it.
+	pending := New[int]()
+	mustFail(t, All(canceled[int](errFirst), pending), errFirst)
+	mustFail(t, All(pending, canceled[int](errSecond)), errSecond)
+}
+
+func TestZip(t *testing.T) {
+	checkNoLeaks(t)
+
+	pair := mustGet(t, Zip2(resolved(1), resolved("a")))
+	if pair.First != 1 || pair.Second != "a" {
+		t.Errorf("got %+v", pair)
+	}
+	triple := mustGet(t, Zip3(resolved(1), resolved("a"), resolved(true)))
+	if triple.First != 1 || triple.Second != "a" || !triple.Third {
+		t.Errorf("got %+v", triple)
+	}
+
+	pending := New[string]()
+	mustFail(t, Zip2(canceled[int](errFirst), pending), errFirst, ErrFutureCanceled)
+	mustFail(t, Zip3(resolved(1), pending, canceled[bool](errSecond)), errSecond)
+}
+
+func TestAny(t *testing.T) {
+	checkNoLeaks(t)
+
+	pending := New[int]()
+	if got := mustGet(t, Any(canceled[int](errFirst), pending, resolved(3))); got != 3 {
+		t.Errorf("got %d, want the only set value", got)
+	}
+
+	mustFail(t, Any(canceled[int](errFirst), canceled[int](errSecond)), errFirst, errSecond, ErrFutureCanceled)
+	mustFail(t, Any[int](), ErrFutureCanceled)
+}
+
+func TestAnyReturnsFirstSet(t *testing.T) {
+	checkNoLeaks(t)
+
+	first, second := New[int](), New[int]()
+	winner := Any(first, second)
+	second.Set(2)
+	if got := mustGet(t, winner); got != 2 {
+		t.Errorf("got %d, want value set first", got)
+	}
+	first.Set(1)
+}
+
+func TestCombinatorsConcurrently(t *testing.T) {
+	checkNoLeaks(t)
+
+	const n = 100
+	for range 50 {
+		fs := make([]*Future[int], n)
+		for i := range fs {
+			fs[i] = New[int]()
+		}
+		all, some := All(fs...), Any(fs...)
+		pair := Zip2(fs[0], fs[n-1])
+
+		var wg sync.WaitGroup
+		for i, f := range fs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				f.Set(i)
+				f.Cancel()
+			}()
+		}
+		wg.Wait()
+
+		values := mustGet(t, all)
+		for i, v := range values {
+			if v != i {
+				t.Fatalf("value %d is %d", i, v)
+			}
+		}
+		if v := mustGet(t, some); v < 0 || v >= n {
+			t.Fatalf("any returned %d", v)
+		}
+		if p := mustGet(t, pair); p.First != 0 || p.Second != n-1 {
+			t.Fatalf("zip returned %+v", p)
+		}
+	}
+}
+
+func TestAllConcurrentCancel(t *testing.T) {
+	checkNoLeaks(t)
+
+	for range 50 {
+		fs := make([]*Future[int], 10)
+		for i := range fs {
+			fs[i] = New[int]()
+		}
+		all := All(fs...)
+
+		var wg sync.WaitGroup
+		for i, f := range fs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if i%2 == 0 {
+					f.CancelWithError(errFirst)
+				} else {
+					f.Set(i)
+				}
+			}()
+		}
+		wg.Wait()
+		mustFail(t, all, errFirst)
+	}
+}
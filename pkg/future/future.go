@@ -1,13 +1,22 @@
 package future
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
 
 var ErrFutureCanceled = errors.New("future canceled")
 
+// Future is a value which becomes available later. It is resolved exactly
+// once, either set or canceled: the first of Set, Cancel and
+// CancelWithError wins and the following calls do nothing.
 type Future[T any] struct {
-	value    T
-	canceled bool
-	c        chan struct{}
+	mu    sync.Mutex
+	value T
+	err   error
+	c     chan struct{}
 }
 
 func New[T any]() *Future[T] {
@@ -16,20 +25,87 @@ func New[T any]() *Future[T] {
 	}
 }
 
-func (m *Future[T]) Set(value T) {
-	m.value = value
-	close(m.c)
+// Set resolves future with value. It reports whether future was resolved
+// by this call.
+func (m *Future[T]) Set(value T) bool {
+	return m.resolve(value, nil)
+}
+
+// Cancel resolves future with ErrFutureCanceled. It reports whether future
+// was resolved by this call.
+func (m *Future[T]) Cancel() bool {
+	return m.CancelWithError(nil)
+}
+
+// CancelWithError resolves future with err, so that consumers see why value
+// is not available. Returned error matches both ErrFutureCanceled and err
+// with errors.Is. It reports whether future was resolved by this call.
+func (m *Future[T]) CancelWithError(err error) bool {
+	if err == nil {
+		err = ErrFutureCanceled
+	} else {
+		err = &canceledError{cause: err}
+	}
+	return m.resolve(*new(T), err)
 }
 
-func (m *Future[T]) Cancel() {
-	m.canceled = true
+func (m *Future[T]) resolve(value T, err error) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	select {
+	case <-m.c:
+		return false
+	default:
+	}
+	m.value, m.err = value, err
 	close(m.c)
+	return true
+}
+
+// Done returns channel which is closed once future is resolved.
+func (m *Future[T]) Done() <-chan struct{} {
+	return m.c
 }
 
+// Get waits for future to be resolved.
 func (m *Future[T]) Get() (T, error) {
 	<-m.c
-	if m.canceled {
-		return *new(T), ErrFutureCanceled
+	return m.result()
+}
+
+// GetContext waits for future to be resolved or ctx to be done, whichever
+// happens first.
+func (m *Future[T]) GetContext(ctx context.Context) (T, error) {
+	select {
+	case <-m.c:
+		return m.result()
+	case <-ctx.Done():
+		return *new(T), ctx.Err()
 	}
-	return m.value, nil
+}
+
+// GetTimeout waits for future to be resolved at most d, returning
+// context.DeadlineExceeded after that.
+func (m *Future[T]) GetTimeout(d time.Duration) (T, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return m.GetContext(ctx)
+}
+
+func (m *Future[T]) result() (T, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.value, m.err
+}
+
+type canceledError struct {
+	cause error
+}
+
+func (e *canceledError) Error() string {
+	return e.cause.Error()
+}
+
+func (e *canceledError) Unwrap() []error {
+	return []error{ErrFutureCanceled, e.cause}
 }
@@ -0,0 +1,202 @@
+package future
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSet(t *testing.T) {
+	checkNoLeaks(t)
+
+	f := New[int]()
+	select {
+	case <-f.Done():
+		t.Fatal("new future is done")
+	default:
+	}
+
+	if !f.Set(1) {
+		t.Fatal("first Set did not resolve future")
+	}
+	if got := mustGet(t, f); got != 1 {
+		t.Errorf("got %d, want 1", got)
+	}
+	<-f.Done()
+}
+
+func TestResolvedOnce(t *testing.T) {
+	checkNoLeaks(t)
+
+	f := New[int]()
+	f.Set(1)
+	if f.Set(2) || f.Cancel() || f.CancelWithError(errFirst) {
+		t.Error("resolved future was resolved again")
+	}
+	if got := mustGet(t, f); got != 1 {
+		t.Errorf("got %d, want value of the first Set", got)
+	}
+
+	f = New[int]()
+	f.CancelWithError(errFirst)
+	if f.Set(2) || f.Cancel() || f.CancelWithError(errSecond) {
+		t.Error("canceled future was resolved again")
+	}
+	err := mustFail(t, f, errFirst, ErrFutureCanceled)
+	if errors.Is(err, errSecond) {
+		t.Errorf("error %q matches cause of the second cancel", err)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	checkNoLeaks(t)
+
+	f := New[int]()
+	if !f.Cancel() {
+		t.Fatal("Cancel did not resolve future")
+	}
+	if err := mustFail(t, f, ErrFutureCanceled); err != ErrFutureCanceled {
+		t.Errorf("got %q, want ErrFutureCanceled itself", err)
+	}
+
+	f = New[int]()
+	f.CancelWithError(nil)
+	mustFail(t, f, ErrFutureCanceled)
+
+	f = New[int]()
+	f.CancelWithError(errFirst)
+	if err := mustFail(t, f, errFirst, ErrFutureCanceled); err.Error() != errFirst.Error() {
+		t.Errorf("got %q, want text of the cause", err)
+	}
+}
+
+func TestGetContext(t *testing.T) {
+	checkNoLeaks(t)
+
+	f := New[int]()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := f.GetContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+	if _, err := f.GetTimeout(time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want context.DeadlineExceeded", err)
+	}
+
+	f.Set(1)
+	if got, err := f.GetContext(context.Background()); err != nil || got != 1 {
+		t.Errorf("got %d, %v, want 1", got, err)
+	}
+}
+
+func TestConcurrentGet(t *testing.T) {
+	checkNoLeaks(t)
+
+	f := New[int]()
+	const readers = 100
+	results := make(chan int, readers)
+	var wg sync.WaitGroup
+	for range readers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := f.Get()
+			if err != nil {
+				t.Errorf("get: %v", err)
+			}
+			results <- value
+		}()
+	}
+	f.Set(42)
+	wg.Wait()
+	close(results)
+
+	for value := range results {
+		if value != 42 {
+			t.Fatalf("reader got %d, want 42", value)
+		}
+	}
+}
+
+func TestConcurrentResolve(t *testing.T) {
+	checkNoLeaks(t)
+
+	for range 100 {
+		f := New[int]()
+		var wins sync.WaitGroup
+		won := make(chan int, 20)
+		for i := range 20 {
+			wins.Add(1)
+			go func() {
+				defer wins.Done()
+				var ok bool
+				switch i % 3 {
+				case 0:
+					ok = f.Set(i)
+				case 1:
+					ok = f.Cancel()
+				default:
+					ok = f.CancelWithError(errFirst)
+				}
+				if ok {
+					won <- i
+				}
+			}()
+			go f.Get()
+		}
+		wins.Wait()
+		close(won)
+
+		if len(won) != 1 {
+			t.Fatalf("%d calls resolved future, want exactly one", len(won))
+		}
+		winner := <-won
+		value, err := f.Get()
+		switch winner % 3 {
+		case 0:
+			if err != nil || value != winner {
+				t.Fatalf("got %d, %v after Set(%d) won", value, err, winner)
+			}
+		case 1:
+			if err != ErrFutureCanceled {
+				t.Fatalf("got %v after Cancel won", err)
+			}
+		default:
+			if !errors.Is(err, errFirst) {
+				t.Fatalf("got %v after CancelWithError won", err)
+			}
+		}
+	}
+}
+
+func BenchmarkSetGet(b *testing.B) {
+	for range b.N {
+		f := New[int]()
+		f.Set(1)
+		f.Get()
+	}
+}
+
+func BenchmarkGetConcurrent(b *testing.B) {
+	f := New[int]()
+	f.Set(1)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			f.Get()
+		}
+	})
+}
+
+func BenchmarkAll(b *testing.B) {
+	fs := make([]*Future[int], 100)
+	for i := range fs {
+		fs[i] = New[int]()
+		fs[i].Set(i)
+	}
+	b.ResetTimer()
+	for range b.N {
+		All(fs...).Get()
+	}
+}
@@ -1,36 +1,156 @@
 package launcher
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"sync"
+	"time"
 )
 
+// Launcher runs tasks concurrently and collects their errors.
 type Launcher struct {
-	errs chan error
-	wg   *sync.WaitGroup
+	ctx           context.Context
+	cancel        context.CancelCauseFunc
+	cancelOnError bool
+	sem           chan struct{}
+	wg            *sync.WaitGroup
+
+	mu    sync.Mutex
+	errs  []error
+	spans []Span
 }
 
 func New() *Launcher {
+	l, _ := WithContext(context.Background())
+	return l
+}
+
+// WithContext returns launcher and context derived from ctx. The context is
+// canceled when Wait returns, or when the first task fails if
+// SetCancelOnError is enabled. Tasks which have not started by the time
+// context is canceled are skipped.
+func WithContext(ctx context.Context) (*Launcher, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
 	return &Launcher{
-		errs: make(chan error, 1000),
-		wg:   &sync.WaitGroup{},
+		ctx:    ctx,
+		cancel: cancel,
+		wg:     &sync.WaitGroup{},
+	}, ctx
+}
+
+// SetCancelOnError makes the first failed task cancel launcher context, so
+// that remaining tasks are skipped.
+func (l *Launcher) SetCancelOnError(enabled bool) {
+	l.cancelOnError = enabled
+}
+
+// SetLimit limits number of concurrently running tasks to n, non-positive n
+// removes the limit. It must be called before launching any task. Tasks
+// which wait for each other must not be limited, otherwise waiting ones may
+// occupy every slot.
+func (l *Launcher) SetLimit(n int) {
+	if n <= 0 {
+		l.sem = nil
+		return
 	}
+	l.sem = make(chan struct{}, n)
 }
 
+// Go runs f in a new goroutine. Its error is returned by Wait as is.
 func (l *Launcher) Go(f func() error) {
+	l.GoNamed("", f)
+}
+
+// GoNamed runs f in a new goroutine. Its error is returned by Wait prefixed
+// with name. Panic in f is recovered and returned as *PanicError.
+func (l *Launcher) GoNamed(name string, f func() error) {
+	l.mu.Lock()
+	idx := len(l.spans)
+	span := Span{Name: name, Outcome: Skipped}
+	if span.Name == "" {
+		span.Name = fmt.Sprintf("task %d", idx+1)
+	}
+	l.spans = append(l.spans, span)
+	l.mu.Unlock()
+
 	l.wg.Add(1)
 	go func() {
 		defer l.wg.Done()
-		l.errs <- f()
+		if !l.acquire() {
+			return
+		}
+		defer l.release()
+
+		span.Start = time.Now()
+		panicked, err := run(name, f)
+		span.End = time.Now()
+		span.Err = err
+		switch {
+		case panicked:
+			span.Outcome = Panicked
+		case err != nil:
+			span.Outcome = Failed
+		default:
+			span.Outcome = Succeeded
+		}
+		l.mu.Lock()
+		l.spans[idx] = span
+		l.mu.Unlock()
+
+		if err != nil {
+			if name != "" && !panicked {
+				err = fmt.Errorf("%s: %w", name, err)
+			}
+			l.fail(err)
+		}
 	}()
 }
 
+func run(name string, f func() error) (panicked bool, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			panicked, err = true, newPanicError(name, v)
+		}
+	}()
+	return false, f()
+}
+
+func (l *Launcher) acquire() bool {
+	if l.sem != nil {
+		select {
+		case l.sem <- struct{}{}:
+		case <-l.ctx.Done():
+			return false
+		}
+	}
+	if l.ctx.Err() != nil {
+		l.release()
+		return false
+	}
+	return true
+}
+
+func (l *Launcher) release() {
+	if l.sem != nil {
+		<-l.sem
+	}
+}
+
+func (l *Launcher) fail(err error) {
+	l.mu.Lock()
+	l.errs = append(l.errs, err)
+	l.mu.Unlock()
+	if l.cancelOnError {
+		l.cancel(err)
+	}
+}
+
+// Wait waits for every launched task and returns their joined errors.
 func (l *Launcher) Wait() error {
 	l.wg.Wait()
-	close(l.errs)
-	var err error
-	for err2 := range l.errs {
-		err = errors.Join(err, err2)
-	}
-	return err
+	l.cancel(nil)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return errors.Join(l.errs...)
 }
@@ -0,0 +1,260 @@
+package launcher
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var (
+	errFirst  = errors.New("first")
+	errSecond = errors.New("second")
+)
+
+// checkNoLeaks fails t if goroutines started during the test are still
+// running after it, giving them a second to finish.
+func checkNoLeaks(t *testing.T) {
+	t.Helper()
+	before := runtime.NumGoroutine()
+	t.Cleanup(func() {
+		deadline := time.Now().Add(time.Second)
+		for runtime.NumGoroutine() > before {
+			if time.Now().After(deadline) {
+				buf := make([]byte, 1<<20)
+				buf = buf[:runtime.Stack(buf, true)]
+				t.Errorf("%d goroutines leaked:\n%s", runtime.NumGoroutine()-before, buf)
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	})
+}
+
+func TestWaitJoinsErrors(t *testing.T) {
+	checkNoLeaks(t)
+
+	l := New()
+	l.Go(func() error { return errFirst })
+	l.GoNamed("second", func() error { return errSecond })
+	l.Go(func() error { return nil })
+
+	err := l.Wait()
+	if !errors.Is(err, errFirst) || !errors.Is(err, errSecond) {
+		t.Fatalf("error %q does not match every task error", err)
+	}
+	if !strings.Contains(err.Error(), "second: second") {
+		t.Errorf("error %q is not prefixed with task name", err)
+	}
+	if len(err.(interface{ Unwrap() []error }).Unwrap()) != 2 {
+		t.Errorf("error %q joins unexpected number of errors", err)
+	}
+}
+
+func TestWaitWithoutErrors(t *testing.T) {
+	checkNoLeaks(t)
+
+	l := New()
+	var runs atomic.Int32
+	for range 10 {
+		l.Go(func() error {
+			runs.Add(1)
+			return nil
+		})
+	}
+	if err := l.Wait(); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+	if runs.Load() != 10 {
+		t.Errorf("%d of 10 tasks ran", runs.Load())
+	}
+	if err := New().Wait(); err != nil {
+		t.Errorf("wait without tasks: %v", err)
+	}
+}
+
+func TestCancelOnErrorFansOut(t *testing.T) {
+	checkNoLeaks(t)
+
+	l, ctx := WithContext(context.Background())
+	l.SetCancelOnError(true)
+
+	const waiters = 5
+	var started sync.WaitGroup
+	var canceled atomic.Int32
+	for range waiters {
+		started.Add(1)
+		l.Go(func() error {
+			started.Done()
+			<-ctx.Done()
+			if errors.Is(context.Cause(ctx), errFirst) {
+				canceled.Add(1)
+			}
+			return nil
+		})
+	}
+	// Tasks which haven't started by the time of failure are skipped, so
+	// the failing one is launched once every waiter runs.
+	started.Wait()
+	l.GoNamed("failing", func() error { return errFirst })
+
+	if err := l.Wait(); !errors.Is(err, errFirst) {
+		t.Fatalf("wait: %v", err)
+	}
+	if canceled.Load() != waiters {
+		t.Errorf("%d of %d tasks saw the cause of cancellation", canceled.Load(), waiters)
+	}
+}
+
+func TestCancelOnErrorSkipsPendingTasks(t *testing.T) {
+	checkNoLeaks(t)
+
+	l, _ := WithContext(context.Background())
+	l.SetCancelOnError(true)
+	l.SetLimit(1)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	l.GoNamed("failing", func() error {
+		close(started)
+		<-release
+		return errFirst
+	})
+	<-started
+	var ran atomic.Bool
+	l.GoNamed("pending", func() error {
+		ran.Store(true)
+		return nil
+	})
+	close(release)
+
+	if err := l.Wait(); !errors.Is(err, errFirst) {
+		t.Fatalf("wait: %v", err)
+	}
+	if ran.Load() {
+		t.Error("task waiting for a slot ran after launcher was canceled")
+	}
+	spans := l.Spans()
+	if spans[0].Outcome != Failed || spans[1].Outcome != Skipped {
+		t.Errorf("outcomes are %s and %s, want failed and skipped", spans[0].Outcome, spans[1].Outcome)
+	}
+}
+
+func TestContextCanceledAfterWait(t *testing.T) {
+	checkNoLeaks(t)
+
+	l, ctx := WithContext(context.Background())
+	l.Go(func() error { return nil })
+	if err := l.Wait(); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+	if ctx.Err() == nil {
+		t.Error("context is not canceled after Wait")
+	}
+
+	// Without SetCancelOnError failures don't cancel other tasks.
+	l, ctx = WithContext(context.Background())
+	l.Go(func() error { return errFirst })
+	time.Sleep(10 * time.Millisecond)
+	if ctx.Err() != nil {
+		t.Error("context is canceled by failure without SetCancelOnError")
+	}
+	l.Wait()
+}
+
+func TestPanicIsRecovered(t *testing.T) {
+	checkNoLeaks(t)
+
+	l := New()
+	l.GoNamed("panicking", func() error { panic("boom") })
+	err := l.Wait()
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("error %q is not a panic error", err)
+	}
+	if panicErr.Task != "panicking" || panicErr.Value != "boom" || len(panicErr.Stack) == 0 {
+		t.Errorf("unexpected panic error %+v", panicErr)
+	}
+	if span := l.Spans()[0]; span.Outcome != Panicked {
+		t.Errorf("outcome is %s, want panicked", span.Outcome)
+	}
+}
+
+func TestLimit(t *testing.T) {
+	checkNoLeaks(t)
+
+	const limit = 3
+	l := New()
+	l.SetLimit(limit)
+
+	var running, peak atomic.Int32
+	for range 20 {
+		l.Go(func() error {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			running.Add(-1)
+			return nil
+		})
+	}
+	if err := l.Wait(); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+	if peak.Load() > limit {
+		t.Errorf("%d tasks ran concurrently, limit is %d", peak.Load(), limit)
+	}
+}
+
+func TestSpans(t *testing.T) {
+	checkNoLeaks(t)
+
+	l := New()
+	l.GoNamed("ok", func() error {
+		time.Sleep(time.Millisecond)
+		return nil
+	})
+	l.Go(func() error { return errFirst })
+	l.Wait()
+
+	spans := l.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	if spans[0].Name != "ok" || spans[0].Outcome != Succeeded || spans[0].Duration() <= 0 {
+		t.Errorf("unexpected span %+v", spans[0])
+	}
+	if spans[1].Name != "task 2" || spans[1].Outcome != Failed || !errors.Is(spans[1].Err, errFirst) {
+		t.Errorf("unexpected span %+v", spans[1])
+	}
+}
+
+func BenchmarkGo(b *testing.B) {
+	for range b.N {
+		l := New()
+		for range 100 {
+			l.Go(func() error { return nil })
+		}
+		l.Wait()
+	}
+}
+
+func BenchmarkGoLimited(b *testing.B) {
+	for range b.N {
+		l := New()
+		l.SetLimit(4)
+		for range 100 {
+			l.Go(func() error { return nil })
+		}
+		l.Wait()
+	}
+}
@@ -0,0 +1,130 @@
+package launcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+type Outcome string
+
+const (
+	Succeeded Outcome = "succeeded"
+	Failed    Outcome = "failed"
+	Panicked  Outcome = "panicked"
+	// Skipped tasks never started, because launcher context was canceled.
+	Skipped Outcome = "skipped"
+)
+
+// Span describes a single task run. Start is the moment task got a slot to
+// run, so time spent waiting for the concurrency limit is not included.
+type Span struct {
+	Name    string
+	Start   time.Time
+	End     time.Time
+	Outcome Outcome
+	Err     error
+}
+
+func (s Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// PanicError is returned by Wait for tasks which panicked.
+type PanicError struct {
+	Task  string
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	task := e.Task
+	if task == "" {
+		task = "unnamed task"
+	}
+	return fmt.Sprintf("%s panicked: %v\n%s", task, e.Value, e.Stack)
+}
+
+func newPanicError(task string, value any) *PanicError {
+	return &PanicError{Task: task, Value: value, Stack: debug.Stack()}
+}
+
+// Spans returns spans of every launched task in launch order. It is meant
+// to be called after Wait.
+func (l *Launcher) Spans() []Span {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	spans := make([]Span, len(l.spans))
+	copy(spans, l.spans)
+	return spans
+}
+
+// WriteTimings writes a table with duration and outcome of every task.
+func WriteTimings(w io.Writer, spans []Span) error {
+	var first time.Time
+	for _, s := range spans {
+		if s.Outcome != Skipped && (first.IsZero() || s.Start.Before(first)) {
+			first = s.Start
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TASK\tSTART\tDURATION\tOUTCOME")
+	for _, s := range spans {
+		if s.Outcome == Skipped {
+			fmt.Fprintf(tw, "%s\t-\t-\t%s\n", s.Name, s.Outcome)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t+%s\t%s\t%s\n", s.Name,
+			s.Start.Sub(first).Round(time.Millisecond), s.Duration().Round(time.Millisecond), s.Outcome)
+	}
+	return tw.Flush()
+}
+
+type traceEvent struct {
+	Name     string            `json:"name"`
+	Category string            `json:"cat"`
+	Phase    string            `json:"ph"`
+	Time     int64             `json:"ts"`
+	Duration int64             `json:"dur"`
+	PID      int               `json:"pid"`
+	TID      int               `json:"tid"`
+	Args     map[string]string `json:"args,omitempty"`
+}
+
+// WriteChromeTrace writes spans in Chrome trace event format, which can be
+// opened in chrome://tracing or Perfetto. Every task gets its own row.
+func WriteChromeTrace(w io.Writer, spans []Span) error {
+	events := make([]traceEvent, 0, len(spans))
+	for i, s := range spans {
+		if s.Outcome == Skipped {
+			continue
+		}
+		args := map[string]string{"outcome": string(s.Outcome)}
+		if s.Err != nil {
+			// Stacks of panics would make the trace viewer unreadable.
+			args["error"], _, _ = strings.Cut(s.Err.Error(), "\n")
+		}
+		events = append(events, traceEvent{
+			Name:     s.Name,
+			Category: "task",
+			Phase:    "X",
+			Time:     s.Start.UnixMicro(),
+			Duration: s.End.Sub(s.Start).Microseconds(),
+			PID:      1,
+			TID:      i + 1,
+			Args:     args,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		TraceEvents     []traceEvent `json:"traceEvents"`
+		DisplayTimeUnit string       `json:"displayTimeUnit"`
+	}{events, "ms"})
+}
@@ -16,6 +16,9 @@ VALUES (@user_id, @number);
 -- name: SelectUserCardIDs :many
 SELECT id FROM user_cards;
 
+-- name: SelectUserCardOwners :many
+SELECT id, user_id FROM user_cards;
+
 -- name: CreateOrders :copyfrom
 INSERT INTO orders (user_id, timestamp, source_address, target_address, courier_id, status, payment_id)
 VALUES (@user_id, @timestamp, @source_address, @target_address, @courier_id, @status, @payment_id);
@@ -23,9 +26,39 @@ VALUES (@user_id, @timestamp, @source_address, @target_address, @courier_id, @st
 -- name: SelectOrderIDs :many
 SELECT id FROM orders;
 
+-- name: SelectOrderTimestamps :many
+SELECT id, timestamp, payment_id FROM orders;
+
+-- name: CreateOrderStatusHistory :copyfrom
+INSERT INTO order_status_history (order_id, status, timestamp)
+VALUES (@order_id, @status, @timestamp);
+
+-- name: SyncOrderStatuses :execrows
+-- Sets status of every order to the latest one from its history.
+UPDATE orders o
+SET status = h.status
+FROM (
+    SELECT DISTINCT ON (order_id) order_id, status
+    FROM order_status_history
+    ORDER BY order_id, timestamp DESC, id DESC
+) h
+WHERE o.id = h.order_id AND o.status <> h.status;
+
 -- name: AssignOrdersCommoditiesAndDishes :copyfrom
-INSERT INTO orders_composition (order_id, dish_id, commodity_id)
-VALUES (@order_id, sqlc.narg('dish_id'), sqlc.narg('commodity_id'));
+INSERT INTO orders_composition (order_id, dish_id, commodity_id, unit_price, quantity)
+VALUES (@order_id, sqlc.narg('dish_id'), sqlc.narg('commodity_id'), @unit_price, @quantity);
+
+-- name: FillOrderAmounts :execrows
+-- Stores amounts of orders created without them, using line prices and
+-- discounts active at the moment.
+UPDATE orders o
+SET total_amount = COALESCE((
+        SELECT SUM(oc.unit_price * oc.quantity)
+        FROM orders_composition oc
+        WHERE oc.order_id = o.id
+    ), 0),
+    discount_amount = order_discount_amount(o.id)
+WHERE o.total_amount IS NULL;
 
 -- name: CreatePayments :copyfrom
 INSERT INTO payments (method, card_id, timestamp, status)
@@ -34,6 +67,12 @@ VALUES (@method, @card_id, @timestamp, @status);
 -- name: SelectPaymentIDs :many
 SELECT id FROM payments;
 
+-- name: SelectPaymentCards :many
+SELECT id, card_id FROM payments;
+
+-- name: SelectPaymentStatuses :many
+SELECT id, status FROM payments;
+
 -- name: CreateCourieres :copyfrom
 INSERT INTO couriers (name, phone, rating)
 VALUES (@name, @phone, @rating);
@@ -48,6 +87,9 @@ VALUES (@supplier_id, @name, @cost, @image, @ingredients, @weight, @calories, @a
 -- name: SelectDishIDs :many
 SELECT id FROM dishes;
 
+-- name: SelectDishCosts :many
+SELECT id, cost FROM dishes;
+
 -- name: CreateCommodities :copyfrom
 INSERT INTO commodities (supplier_id, name, cost, image, ingredients, weight, rating)
 VALUES (@supplier_id, @name, @cost, @image, @ingredients, @weight, @rating);
@@ -55,6 +97,9 @@ VALUES (@supplier_id, @name, @cost, @image, @ingredients, @weight, @rating);
 -- name: SelectCommodityIDs :many
 SELECT id FROM commodities;
 
+-- name: SelectCommodityCosts :many
+SELECT id, cost FROM commodities;
+
 -- name: CreateCategories :copyfrom
 INSERT INTO categories (name)
 VALUES (@name);
@@ -83,3 +128,149 @@ SELECT id FROM discounts;
 -- name: CreateDiscountTargets :copyfrom
 INSERT INTO discount_to_targets (dish_id, commodity_id, discount_id)
 VALUES (sqlc.narg('dish_id'), sqlc.narg('commodity_id'), @discount_id);
+
+-- name: SelectOrderLines :many
+SELECT oc.id, oc.dish_id, oc.commodity_id, oc.unit_price, oc.quantity
+FROM orders_composition oc
+WHERE oc.order_id = @order_id
+ORDER BY oc.id;
+
+-- name: SelectApplicableDiscounts :many
+-- Active discounts targeting items of the order, and discount attached to
+-- the order itself if it has no targets (target columns are NULL then).
+SELECT ds.id, ds.type, ds.terms, dt.dish_id, dt.commodity_id
+FROM discounts ds
+LEFT JOIN discount_to_targets dt ON ds.id = dt.discount_id
+WHERE ds.active
+AND (
+    dt.dish_id IN (SELECT oc.dish_id FROM orders_composition oc WHERE oc.order_id = @order_id)
+    OR dt.commodity_id IN (SELECT oc.commodity_id FROM orders_composition oc WHERE oc.order_id = @order_id)
+    OR (dt.id IS NULL AND ds.id = (SELECT o.discount_id FROM orders o WHERE o.id = @order_id))
+)
+ORDER BY ds.id;
+
+-- name: SelectOrderStatusForUpdate :one
+SELECT status FROM orders WHERE id = @id FOR UPDATE;
+
+-- name: UpdateOrderStatus :exec
+UPDATE orders SET status = @status WHERE id = @id;
+
+-- name: CreateOrderStatus :exec
+INSERT INTO order_status_history (order_id, status, timestamp)
+VALUES (@order_id, @status, @timestamp);
+
+-- name: SelectOrderStatusHistory :many
+SELECT * FROM order_status_history
+WHERE order_id = @order_id
+ORDER BY timestamp, id;
+
+-- name: CreatePayment :one
+INSERT INTO payments (method, card_id, status)
+VALUES (@method, @card_id, @status)
+RETURNING id;
+
+-- name: CreateOrder :one
+INSERT INTO orders (user_id, timestamp, source_address, target_address, courier_id, status, payment_id)
+VALUES (@user_id, @timestamp, @source_address, @target_address, @courier_id, @status, @payment_id)
+RETURNING id;
+
+-- name: FillOrderAmount :exec
+-- Stores amounts of a single order, the same way FillOrderAmounts does.
+UPDATE orders o
+SET total_amount = COALESCE((
+        SELECT SUM(oc.unit_price * oc.quantity)
+        FROM orders_composition oc
+        WHERE oc.order_id = o.id
+    ), 0),
+    discount_amount = order_discount_amount(o.id)
+WHERE o.id = @id;
+
+-- name: SelectOrderStatus :one
+SELECT status FROM orders WHERE id = @id;
+
+-- name: SelectSupplier :one
+SELECT * FROM suppliers WHERE id = @id;
+
+-- name: SelectSupplierDishes :many
+SELECT * FROM dishes
+WHERE supplier_id = @supplier_id
+ORDER BY rating DESC, id;
+
+-- name: SelectSupplierCommodities :many
+SELECT * FROM commodities
+WHERE supplier_id = @supplier_id
+ORDER BY rating DESC, id;
+
+-- name: SelectCourier :one
+SELECT * FROM couriers WHERE id = @id;
+
+-- name: SelectCourierActiveOrders :many
+-- Orders accepted by courier which are not delivered yet.
+SELECT id, timestamp, source_address, target_address, status FROM orders
+WHERE courier_id = @courier_id AND status IN ('accepted', 'picked_up')
+ORDER BY timestamp;
+
+-- name: SearchDishesByIngredient :many
+SELECT id, supplier_id, name, cost, rating FROM dishes
+WHERE ingredients ILIKE @pattern
+ORDER BY rating DESC, id
+LIMIT @max_results;
+
+-- name: ListUsers :many
+-- Users without password hashes, optionally filtered by surname prefix.
+SELECT id, name, surname, email, phone FROM users
+WHERE (sqlc.narg('surname')::TEXT IS NULL OR surname LIKE sqlc.narg('surname') || '%')
+ORDER BY id
+LIMIT @page_size OFFSET @page_offset;
+
+-- name: SelectUser :one
+SELECT id, name, surname, email, phone FROM users WHERE id = @id;
+
+-- name: ListSuppliers :many
+SELECT * FROM suppliers
+WHERE (sqlc.narg('name')::TEXT IS NULL OR name ILIKE '%' || sqlc.narg('name') || '%')
+ORDER BY id
+LIMIT @page_size OFFSET @page_offset;
+
+-- name: ListDishes :many
+SELECT * FROM dishes
+WHERE (sqlc.narg('supplier_id')::INT IS NULL OR supplier_id = sqlc.narg('supplier_id'))
+AND (sqlc.narg('name')::TEXT IS NULL OR name LIKE sqlc.narg('name') || '%')
+AND (sqlc.narg('ingredient')::TEXT IS NULL OR ingredients ILIKE '%' || sqlc.narg('ingredient') || '%')
+AND (sqlc.narg('min_cost')::BIGINT IS NULL OR cost >= sqlc.narg('min_cost'))
+AND (sqlc.narg('max_cost')::BIGINT IS NULL OR cost <= sqlc.narg('max_cost'))
+AND (sqlc.narg('min_rating')::NUMERIC IS NULL OR rating >= sqlc.narg('min_rating'))
+AND (sqlc.narg('max_calories')::INT IS NULL OR calories <= sqlc.narg('max_calories'))
+ORDER BY id
+LIMIT @page_size OFFSET @page_offset;
+
+-- name: SelectDish :one
+SELECT * FROM dishes WHERE id = @id;
+
+-- name: ListCommodities :many
+SELECT * FROM commodities
+WHERE (sqlc.narg('supplier_id')::INT IS NULL OR supplier_id = sqlc.narg('supplier_id'))
+AND (sqlc.narg('name')::TEXT IS NULL OR name LIKE sqlc.narg('name') || '%')
+AND (sqlc.narg('ingredient')::TEXT IS NULL OR ingredients ILIKE '%' || sqlc.narg('ingredient') || '%')
+AND (sqlc.narg('min_cost')::BIGINT IS NULL OR cost >= sqlc.narg('min_cost'))
+AND (sqlc.narg('max_cost')::BIGINT IS NULL OR cost <= sqlc.narg('max_cost'))
+AND (sqlc.narg('min_rating')::NUMERIC IS NULL OR rating >= sqlc.narg('min_rating'))
+ORDER BY id
+LIMIT @page_size OFFSET @page_offset;
+
+-- name: SelectCommodity :one
+SELECT * FROM commodities WHERE id = @id;
+
+-- name: ListOrders :many
+-- Orders from the most recent, timestamps are filtered by [since, until).
+SELECT * FROM orders
+WHERE (sqlc.narg('user_id')::INT IS NULL OR user_id = sqlc.narg('user_id'))
+AND (sqlc.narg('courier_id')::INT IS NULL OR courier_id = sqlc.narg('courier_id'))
+AND (sqlc.narg('status')::TEXT IS NULL OR status = sqlc.narg('status'))
+AND (sqlc.narg('since')::TIMESTAMP IS NULL OR timestamp >= sqlc.narg('since'))
+AND (sqlc.narg('until')::TIMESTAMP IS NULL OR timestamp < sqlc.narg('until'))
+ORDER BY timestamp DESC, id DESC
+LIMIT @page_size OFFSET @page_offset;
+
+-- name: SelectOrder :one
+SELECT * FROM orders WHERE id = @id;
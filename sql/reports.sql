@@ -0,0 +1,532 @@
+-- name: SuppliersOfCommodity :many
+-- Найти всех поставщиков определенного товара и вывести о поставщиках инфо
+SELECT DISTINCT s.*
+FROM suppliers s
+INNER JOIN commodities c ON s.id = c.supplier_id
+WHERE c.name = @commodity_name;
+
+-- name: CommoditiesOfSupplier :many
+-- Найти все товары определенного поставщика и вывести инфо о товарах
+SELECT c.*
+FROM commodities c
+WHERE c.supplier_id = @supplier_id;
+
+-- name: FailedCardPayments :many
+-- Найти все карты по которым не прошла оплата и вывести инфо об этой операции
+SELECT uc.*, p.timestamp as payment_time, p.status as payment_status, o.id as order_id
+FROM user_cards uc
+JOIN payments p ON uc.id = p.card_id
+JOIN orders o ON p.id = o.payment_id
+WHERE p.status = 'failed';
+
+-- name: OrdersLastMonth :many
+-- Найти все заказы сделанные за последний месяц и вывести по ним инфу
+SELECT o.*, p.method as payment_method, p.status as payment_status,
+       c.name as courier_name, c.rating as courier_rating
+FROM orders o
+LEFT JOIN payments p ON o.payment_id = p.id
+LEFT JOIN couriers c ON o.courier_id = c.id
+WHERE o.timestamp >= (CURRENT_DATE - INTERVAL '1 month')
+ORDER BY o.timestamp DESC;
+
+-- name: CourierOrdersInMonth :many
+-- Найти всех курьеров которые работали в определенном месяце и году и вывести кол-во заказов
+SELECT c.*,
+       COUNT(o.id) as total_orders
+FROM couriers c
+JOIN orders o ON c.id = o.courier_id
+WHERE EXTRACT(YEAR FROM o.timestamp) = @year::INT
+  AND EXTRACT(MONTH FROM o.timestamp) = @month::INT
+GROUP BY c.id;
+
+-- name: DishesOrderedBetween :many
+-- Найти все блюда которые были заказаны с определенной даты по какую-то дату и вывести инфо о заказе
+SELECT d.*, o.timestamp as order_time, o.status as order_status,
+       o.source_address, o.target_address
+FROM dishes d
+JOIN orders_composition oc ON d.id = oc.dish_id
+JOIN orders o ON oc.order_id = o.id
+WHERE o.timestamp >= @since
+  AND o.timestamp < @until::TIMESTAMP + INTERVAL '1 day';
+
+-- name: TopCustomerBySpending :many
+-- Найти клиента который заказал на максимальную сумму за все время (без учета скидок)
+WITH user_totals AS (
+    SELECT user_id, SUM(total)::BIGINT as total_spent
+    FROM order_totals
+    GROUP BY user_id
+    ORDER BY total_spent DESC
+    LIMIT 1
+)
+SELECT u.*, ut.total_spent
+FROM users u
+JOIN user_totals ut ON u.id = ut.user_id;
+
+-- name: TopCustomerByDiscountedSpending :many
+-- Найти клиента который заказал на максимальную сумму за все время (с учетом скидок)
+WITH user_totals AS (
+    SELECT user_id, SUM(discounted_total)::BIGINT as total_spent
+    FROM order_totals
+    GROUP BY user_id
+    ORDER BY total_spent DESC
+    LIMIT 1
+)
+SELECT u.*, ut.total_spent
+FROM users u
+JOIN user_totals ut ON u.id = ut.user_id;
+
+-- name: LeastOrderedDishes :many
+-- Найти блюдо которое было заказано меньше всего раз за все время
+WITH dish_orders AS (
+    SELECT d.id, d.name, COALESCE(SUM(oc.quantity), 0)::BIGINT as order_count
+    FROM dishes d
+    LEFT JOIN orders_composition oc ON d.id = oc.dish_id
+    GROUP BY d.id, d.name
+)
+SELECT d.*, orders.order_count
+FROM dishes d
+JOIN dish_orders orders ON d.id = orders.id
+WHERE orders.order_count = (
+    SELECT MIN(order_count)
+    FROM dish_orders
+);
+
+-- name: SupplierWithMostDishesOrdered :many
+-- Найти заведение из которого было заказано больше всего блюд за все время
+SELECT s.*, SUM(sms.dishes_ordered)::BIGINT as total_dishes_ordered
+FROM suppliers s
+JOIN supplier_monthly_sales sms ON s.id = sms.supplier_id
+GROUP BY s.id
+HAVING SUM(sms.dishes_ordered) > 0
+ORDER BY total_dishes_ordered DESC
+LIMIT 1;
+
+-- name: DishesWithIngredient :many
+-- Найти все блюда в составе которого есть определенный ингредиент
+SELECT *
+FROM dishes
+WHERE ingredients LIKE '%' || @ingredient::TEXT || '%';
+
+-- name: SuppliersOnStreet :many
+-- Найти все заведения на определенной улице
+SELECT *
+FROM suppliers
+WHERE address LIKE '%' || @street::TEXT || '%';
+
+-- name: UsersBySurnamePrefix :many
+-- Найти всех клиентов у которых фамилия начинается на определенную букву
+SELECT *
+FROM users
+WHERE surname LIKE @prefix::TEXT || '%';
+
+-- name: UnorderedSupplierDishes :many
+-- Найти блюда конкретного поставщика, которое не было заказано за последнюю неделю
+SELECT d.*
+FROM dishes d
+WHERE d.supplier_id = @supplier_id
+AND d.id NOT IN (
+    SELECT DISTINCT oc.dish_id
+    FROM orders_composition oc
+    JOIN orders o ON oc.order_id = o.id
+    WHERE o.timestamp >= (CURRENT_DATE - INTERVAL '1 week')
+    AND oc.dish_id IS NOT NULL
+);
+
+-- name: UnorderedSupplierDishesExcept :many
+-- Найти блюда конкретного поставщика, которое не было заказано за последнюю неделю (через вычитание)
+SELECT d.*
+FROM dishes d
+WHERE d.supplier_id = @supplier_id
+EXCEPT
+SELECT d.*
+FROM dishes d
+JOIN orders_composition oc ON d.id = oc.dish_id
+JOIN orders o ON oc.order_id = o.id
+WHERE o.timestamp >= (CURRENT_DATE - INTERVAL '1 week');
+
+-- name: UnorderedSupplierDishesCount :many
+-- Найти блюда конкретного поставщика, которое не было заказано за последнюю неделю (через count)
+SELECT d.*
+FROM dishes d
+WHERE d.supplier_id = @supplier_id
+AND (
+    SELECT COUNT(*)
+    FROM orders_composition oc
+    JOIN orders o ON oc.order_id = o.id
+    WHERE oc.dish_id = d.id
+    AND o.timestamp >= (CURRENT_DATE - INTERVAL '1 week')
+) = 0;
+
+-- name: UsersWithoutRestaurantOrders :many
+-- Найти клиентов, которые никогда не заказывали из ресторанов
+SELECT u.*
+FROM users u
+WHERE NOT EXISTS (
+    SELECT 1
+    FROM orders o
+    JOIN orders_composition oc ON o.id = oc.order_id
+    JOIN dishes d ON oc.dish_id = d.id
+    WHERE o.user_id = u.id
+);
+
+-- name: UsersWithoutRestaurantOrdersCount :many
+-- Найти клиентов, которые никогда не заказывали из ресторанов (через count)
+SELECT u.*
+FROM users u
+WHERE (
+    SELECT COUNT(oc.dish_id)
+    FROM orders o
+    JOIN orders_composition oc ON o.id = oc.order_id
+    WHERE o.user_id = u.id
+) = 0;
+
+-- name: UsersNeverPaidByCard :many
+-- Найти пользователей, которые никогда не платили картой
+SELECT u.*
+FROM users u
+WHERE NOT EXISTS (
+    SELECT 1
+    FROM orders o
+    JOIN payments p ON o.payment_id = p.id
+    WHERE o.user_id = u.id
+    AND p.card_id IS NOT NULL
+);
+
+-- name: UsersNeverPaidByCardExcept :many
+-- Найти пользователей, которые никогда не платили картой (через вычитание)
+SELECT u.*
+FROM users u
+EXCEPT
+SELECT u.*
+FROM users u
+JOIN orders o ON u.id = o.user_id
+JOIN payments p ON o.payment_id = p.id
+WHERE p.card_id IS NOT NULL;
+
+-- name: SingleSupplierCustomers :many
+-- Найти клиентов, которые делают заказы только из одного заведения
+SELECT u.*, COUNT(DISTINCT d.supplier_id) as supplier_count
+FROM users u
+JOIN orders o ON u.id = o.user_id
+JOIN orders_composition oc ON o.id = oc.order_id
+JOIN dishes d ON oc.dish_id = d.id
+GROUP BY u.id
+HAVING COUNT(DISTINCT d.supplier_id) = 1;
+
+-- name: DishesFromSingleSupplier :many
+-- Найти блюда, которые предлагаются только в одном заведении
+SELECT d.name, COUNT(DISTINCT d.supplier_id) as supplier_count
+FROM dishes d
+GROUP BY d.name
+HAVING COUNT(DISTINCT d.supplier_id) = 1;
+
+-- name: SingleAddressCustomers :many
+-- Найти клиентов, которые заказывают доставку только на один адрес
+SELECT u.*, COUNT(DISTINCT o.target_address) as delivery_addresses
+FROM users u
+JOIN orders o ON u.id = o.user_id
+GROUP BY u.id
+HAVING COUNT(DISTINCT o.target_address) = 1;
+
+-- name: TopCustomersBySpending :many
+-- Найти 3 клиента у которых макс сумма заказов по цене за все время
+SELECT u.*, SUM(ot.total)::BIGINT as total_spent
+FROM users u
+JOIN order_totals ot ON u.id = ot.user_id
+WHERE ot.positions > 0
+GROUP BY u.id
+ORDER BY total_spent DESC
+LIMIT 3;
+
+-- name: LeastSellingSuppliersLastMonth :many
+-- Найти 5 производителей, которые продали блюд на наименьшую сумму за последний месяц
+SELECT s.*, COALESCE(SUM(oc.unit_price * oc.quantity), 0)::BIGINT as total_sales
+FROM suppliers s
+LEFT JOIN dishes d ON s.id = d.supplier_id
+LEFT JOIN (
+    orders_composition oc
+    JOIN orders o ON oc.order_id = o.id
+                 AND o.timestamp >= (CURRENT_DATE - INTERVAL '1 month')
+) ON d.id = oc.dish_id
+GROUP BY s.id
+ORDER BY total_sales ASC
+LIMIT 5;
+
+-- name: MostOrderedCategory :many
+-- Найти 1 категорию, в которой было заказано больше всего блюд за все время
+SELECT c.*, SUM(oc.quantity)::BIGINT as dishes_ordered
+FROM categories c
+JOIN categories_to_targets ct ON c.id = ct.category_id
+JOIN dishes d ON ct.dish_id = d.id
+JOIN orders_composition oc ON d.id = oc.dish_id
+GROUP BY c.id
+ORDER BY dishes_ordered DESC
+LIMIT 1;
+
+-- name: AvgMonthlyOrdersPerUser :many
+-- Найти среднее количество заказов клиента в месяц
+SELECT user_id, AVG(orders_count)::FLOAT8 as avg_monthly_orders
+FROM user_monthly_orders
+GROUP BY user_id;
+
+-- name: AvgPositionsPerOrder :one
+-- Найти среднее количество позиций в заказе
+SELECT AVG(positions)::FLOAT8 as avg_positions_per_order
+FROM order_totals;
+
+-- name: AvgDishesPerSupplier :one
+-- Найти среднее количество блюд на продавца
+SELECT AVG(dish_count)::FLOAT8 as avg_dishes_per_supplier
+FROM (
+    SELECT supplier_id, COUNT(id) as dish_count
+    FROM dishes
+    GROUP BY supplier_id
+) supplier_dishes;
+
+-- name: TopSuppliersByRating :many
+-- Найти топ-3 поставщиков по среднему рейтингу их блюд и товаров вместе
+WITH combined_ratings AS (
+    SELECT supplier_id,
+           AVG(rating)::FLOAT8 as avg_rating,
+           COUNT(*) as total_items
+    FROM (
+        SELECT supplier_id, rating FROM dishes
+        UNION ALL
+        SELECT supplier_id, rating FROM commodities
+    ) all_items
+    GROUP BY supplier_id
+)
+SELECT s.*, cr.avg_rating, cr.total_items
+FROM suppliers s
+JOIN combined_ratings cr ON s.id = cr.supplier_id
+ORDER BY cr.avg_rating DESC
+LIMIT 3;
+
+-- name: SupplierPriceRanges :many
+-- Для каждого поставщика посчитать количество блюд и товаров в разных ценовых диапазонах
+SELECT s.id, s.name,
+       COUNT(CASE WHEN item_cost < 500 THEN 1 END) as low_price,
+       COUNT(CASE WHEN item_cost BETWEEN 500 AND 1000 THEN 1 END) as medium_price,
+       COUNT(CASE WHEN item_cost > 1000 THEN 1 END) as high_price
+FROM suppliers s
+LEFT JOIN (
+    SELECT supplier_id, cost as item_cost FROM dishes
+    UNION ALL
+    SELECT supplier_id, cost FROM commodities
+) all_items ON s.id = all_items.supplier_id
+GROUP BY s.id, s.name;
+
+-- name: SuppliersAboveAvgDishRating :many
+-- Найти поставщиков, у которых средний рейтинг блюд выше среднего рейтинга по всем блюдам
+WITH avg_rating AS (
+    SELECT AVG(rating) as avg_dish_rating
+    FROM dishes
+)
+SELECT s.*, AVG(d.rating)::FLOAT8 as supplier_avg_rating
+FROM suppliers s
+JOIN dishes d ON s.id = d.supplier_id
+CROSS JOIN avg_rating ar
+GROUP BY s.id, ar.avg_dish_rating
+HAVING AVG(d.rating) > ar.avg_dish_rating;
+
+-- name: UserMonthlyOrders :many
+-- Найти пользователей и количество их заказов за каждый месяц определенного года
+SELECT u.id, u.name, u.surname,
+       umo.month::TIMESTAMP as month,
+       umo.orders_count
+FROM users u
+JOIN user_monthly_orders umo ON u.id = umo.user_id
+WHERE EXTRACT(YEAR FROM umo.month) = @year::INT
+ORDER BY u.id, month;
+
+-- name: UsersOrderingEveryMonth :many
+-- Определить пользователей, которые сделали заказы во все месяцы определенного года
+SELECT u.*
+FROM users u
+WHERE (
+    SELECT COUNT(*)
+    FROM user_monthly_orders umo
+    WHERE umo.user_id = u.id
+    AND EXTRACT(YEAR FROM umo.month) = @year::INT
+) = 12;
+
+-- name: SupplierBestMonth :many
+-- Найти месяц в определенном году с наибольшим количеством заказов для каждого поставщика
+WITH monthly_orders AS (
+    SELECT supplier_id,
+           month,
+           dishes_ordered as order_count,
+           RANK() OVER (PARTITION BY supplier_id ORDER BY dishes_ordered DESC) as month_rank
+    FROM supplier_monthly_sales
+    WHERE EXTRACT(YEAR FROM month) = @year::INT
+    AND dishes_ordered > 0
+)
+SELECT s.*, mo.month::TIMESTAMP as month, mo.order_count
+FROM suppliers s
+JOIN monthly_orders mo ON s.id = mo.supplier_id
+WHERE mo.month_rank = 1;
+
+-- name: UsersWithAddressesAndCards :many
+-- Найти пользователей, у которых есть и сохраненные адреса, и привязанные карты
+SELECT u.*,
+       COUNT(DISTINCT ua.id) as address_count,
+       COUNT(DISTINCT uc.id) as card_count
+FROM users u
+JOIN user_addresses ua ON u.id = ua.user_id
+JOIN user_cards uc ON u.id = uc.user_id
+GROUP BY u.id;
+
+-- name: UsersWithManyAddressesNoCards :many
+-- Найти пользователей, у которых больше 3 сохраненных адресов, но нет привязанных карт
+SELECT u.*,
+       COUNT(DISTINCT ua.id) as address_count
+FROM users u
+JOIN user_addresses ua ON u.id = ua.user_id
+LEFT JOIN user_cards uc ON u.id = uc.user_id
+WHERE uc.id IS NULL
+GROUP BY u.id
+HAVING COUNT(DISTINCT ua.id) > 3;
+
+-- name: UsersNotUsingSavedAddresses :many
+-- Определить пользователей, которые никогда не использовали свои сохраненные адреса для заказов
+SELECT DISTINCT u.*
+FROM users u
+JOIN user_addresses ua ON u.id = ua.user_id
+WHERE NOT EXISTS (
+    SELECT 1
+    FROM orders o
+    WHERE o.user_id = u.id
+    AND o.target_address = ua.address
+);
+
+-- name: DishesOfferedByManySuppliers :many
+-- Найти блюда, которые есть у более чем 2 поставщиков, и их среднюю цену
+SELECT d.name,
+       COUNT(DISTINCT d.supplier_id) as supplier_count,
+       AVG(d.cost)::FLOAT8 as avg_cost
+FROM dishes d
+GROUP BY d.name
+HAVING COUNT(DISTINCT d.supplier_id) > 2;
+
+-- name: SuppliersWithAllDishesAboveAvg :many
+-- Определить поставщиков, у которых все блюда дороже средней цены по всем блюдам
+WITH avg_price AS (
+    SELECT AVG(cost) as avg_cost
+    FROM dishes
+)
+SELECT s.*
+FROM suppliers s
+WHERE NOT EXISTS (
+    SELECT 1
+    FROM dishes d
+    CROSS JOIN avg_price ap
+    WHERE d.supplier_id = s.id
+    AND d.cost <= ap.avg_cost
+);
+
+-- name: SupplierDishPriceSpread :many
+-- Найти поставщиков с наибольшей разницей в цене между их самым дорогим и самым дешевым блюдом
+SELECT s.*,
+       (MAX(d.cost) - MIN(d.cost))::BIGINT as price_difference,
+       MAX(d.cost)::BIGINT as max_price,
+       MIN(d.cost)::BIGINT as min_price
+FROM suppliers s
+JOIN dishes d ON s.id = d.supplier_id
+GROUP BY s.id
+ORDER BY price_difference DESC;
+
+-- name: TopRatedBusySuppliers :many
+-- Найти поставщиков, у которых средний рейтинг блюд выше 4.5 И количество блюд больше 5
+SELECT s.*,
+       AVG(d.rating)::FLOAT8 as avg_dish_rating,
+       COUNT(d.id) as dish_count
+FROM suppliers s
+JOIN dishes d ON s.id = d.supplier_id
+GROUP BY s.id
+HAVING AVG(d.rating) > 4.5 AND COUNT(d.id) > 5;
+
+-- name: ExpensiveCategories :many
+-- Найти категории, в которых есть более 10 блюд И средняя цена блюд выше общей средней цены
+WITH avg_price AS (
+    SELECT AVG(cost) as avg_cost
+    FROM dishes
+)
+SELECT c.*,
+       COUNT(DISTINCT d.id) as dish_count,
+       AVG(d.cost)::FLOAT8 as avg_category_cost
+FROM categories c
+JOIN categories_to_targets ct ON c.id = ct.category_id
+JOIN dishes d ON ct.dish_id = d.id
+CROSS JOIN avg_price ap
+GROUP BY c.id, ap.avg_cost
+HAVING COUNT(DISTINCT d.id) > 10
+   AND AVG(d.cost) > ap.avg_cost;
+
+-- name: FrequentBigSpenders :many
+-- Найти пользователей, сделавших более 3 заказов И средняя сумма заказа которых выше 1000
+SELECT u.*,
+       COUNT(DISTINCT o.id) as order_count,
+       AVG(os.order_sum)::FLOAT8 as avg_order_sum
+FROM users u
+JOIN orders o ON u.id = o.user_id
+JOIN (
+    SELECT order_id, SUM(unit_price * quantity) as order_sum
+    FROM orders_composition
+    GROUP BY order_id
+) os ON o.id = os.order_id
+GROUP BY u.id
+HAVING COUNT(DISTINCT o.id) > 3
+   AND AVG(os.order_sum) > 1000;
+
+-- name: SupplierSizeCategories :many
+-- Классифицировать поставщиков по количеству блюд: мало (< 5), средне (5-15), много (> 15)
+SELECT s.*,
+       COUNT(d.id) as dish_count,
+       (CASE
+           WHEN COUNT(d.id) < 5 THEN 'low'
+           WHEN COUNT(d.id) <= 15 THEN 'medium'
+           ELSE 'high'
+       END)::TEXT as size_category
+FROM suppliers s
+LEFT JOIN dishes d ON s.id = d.supplier_id
+GROUP BY s.id
+ORDER BY dish_count;
+
+-- name: OrdersByTimeOfDay :many
+-- Разделить заказы на категории по времени доставки: утро (6-12), день (12-18), вечер (18-24), ночь (0-6)
+SELECT o.*,
+       (CASE
+           WHEN EXTRACT(HOUR FROM o.timestamp) BETWEEN 6 AND 11 THEN 'morning'
+           WHEN EXTRACT(HOUR FROM o.timestamp) BETWEEN 12 AND 17 THEN 'afternoon'
+           WHEN EXTRACT(HOUR FROM o.timestamp) BETWEEN 18 AND 23 THEN 'evening'
+           ELSE 'night'
+       END)::TEXT as delivery_time_category
+FROM orders o
+ORDER BY o.timestamp;
+
+-- name: UserActivityLastMonth :many
+-- Категоризировать пользователей по частоте заказов за последний месяц: активные (> 5), средние (2-5), редкие (< 2)
+SELECT u.*,
+       COUNT(o.id) as orders_last_month,
+       (CASE
+           WHEN COUNT(o.id) > 5 THEN 'active'
+           WHEN COUNT(o.id) >= 2 THEN 'average'
+           ELSE 'rare'
+       END)::TEXT as user_category
+FROM users u
+LEFT JOIN orders o ON u.id = o.user_id
+   AND o.timestamp >= (CURRENT_DATE - INTERVAL '1 month')
+GROUP BY u.id;
+
+-- name: DeliveryDurations :many
+-- Время доставки заказов по месяцам создания: среднее, медиана и максимум в минутах от создания до доставки
+SELECT date_trunc('month', c.timestamp)::TIMESTAMP as month,
+       COUNT(*) as delivered_orders,
+       (EXTRACT(EPOCH FROM AVG(d.timestamp - c.timestamp)) / 60)::FLOAT8 as avg_minutes,
+       (EXTRACT(EPOCH FROM percentile_cont(0.5) WITHIN GROUP (ORDER BY d.timestamp - c.timestamp)) / 60)::FLOAT8 as median_minutes,
+       (EXTRACT(EPOCH FROM MAX(d.timestamp - c.timestamp)) / 60)::FLOAT8 as max_minutes
+FROM order_status_history c
+JOIN order_status_history d ON d.order_id = c.order_id AND d.status = 'delivered'
+WHERE c.status = 'created'
+GROUP BY month
+ORDER BY month;